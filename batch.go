@@ -0,0 +1,130 @@
+package jsonschema
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// LineResult is the result of the validation of a line of NDJSON (JSON
+// Lines).
+type LineResult struct {
+	// Line is the line number (starting at 1).
+	Line int
+	Data []byte
+
+	// Value is the validated value (with the updates made during the
+	// validation, like the defaults of WithDefaults).
+	Value interface{}
+
+	// Err is the validation error or the syntax error of the line.
+	Err error
+}
+
+// ValidateLines validates each line of the NDJSON (JSON Lines) read from r
+// on workers goroutines (runtime.GOMAXPROCS(0) when workers <= 0) and calls
+// fn with the results in the order of the lines. Empty lines are skipped.
+// ValidateLines stops at the first error returned by fn (and returns it) or
+// at the first error of r.
+func (s *Schema) ValidateLines(r io.Reader, workers int, fn func(*LineResult) error, options ...ValidateOption) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var (
+		jobs    = make(chan *lineJob)
+		pending = make(chan *lineJob, 4*workers)
+		stop    = make(chan struct{})
+		readErr error
+		wg      sync.WaitGroup
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				job.validate(s, options)
+				close(job.done)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(pending)
+		defer close(jobs)
+
+		reader := bufio.NewReader(r)
+		for line := 1; ; line++ {
+			data, err := reader.ReadBytes('\n')
+
+			data = bytes.TrimRight(data, "\r\n")
+			if len(bytes.TrimSpace(data)) > 0 {
+				// the jobs are queued in pending (in order) before they are
+				// handed to the workers so pending limits the lines in flight
+				job := &lineJob{result: LineResult{Line: line, Data: data}, done: make(chan struct{})}
+				select {
+				case pending <- job:
+				case <-stop:
+					return
+				}
+				select {
+				case jobs <- job:
+				case <-stop:
+					return
+				}
+			}
+
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				readErr = err
+				return
+			}
+		}
+	}()
+
+	var err error
+	for job := range pending {
+		<-job.done
+		err = fn(&job.result)
+		if err != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		close(stop)
+		for range pending {
+		}
+	}
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	return readErr
+}
+
+type lineJob struct {
+	result LineResult
+	done   chan struct{}
+}
+
+func (job *lineJob) validate(s *Schema, options []ValidateOption) {
+	var x interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(job.result.Data))
+	dec.UseNumber()
+	err := dec.Decode(&x)
+	if err != nil {
+		job.result.Err = err
+		return
+	}
+
+	job.result.Value, job.result.Err = validateInPlace(s, x, options)
+}
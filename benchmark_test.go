@@ -1,19 +1,23 @@
-package jsonschema
+package jsonschema_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
 	"testing"
+
+	"github.com/fd/jsonschema"
 )
 
 // See: https://github.com/Sembiance/cosmicrealms.com/blob/master/sandbox/benchmark-of-node-dot-js-json-validation-modules-part-2
 func BenchmarkValid(b *testing.B) {
-	env := RootEnv.Clone()
-	schema, err := env.BuildSchema("", load_test_data("draft4/benchmark/schema4.json"))
+	env := jsonschema.RootEnv.Clone()
+	schema, err := env.BuildSchema("", loadBenchmarkData("schema4.json"))
 	if err != nil {
 		panic(err)
 	}
 
-	var instance interface{}
-	load_test_json("draft4/benchmark/valid.json", &instance)
+	instance := loadBenchmarkInstance("valid.json")
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -27,14 +31,13 @@ func BenchmarkValid(b *testing.B) {
 }
 
 func BenchmarkInvalid(b *testing.B) {
-	env := RootEnv.Clone()
-	schema, err := env.BuildSchema("", load_test_data("draft4/benchmark/schema4.json"))
+	env := jsonschema.RootEnv.Clone()
+	schema, err := env.BuildSchema("", loadBenchmarkData("schema4.json"))
 	if err != nil {
 		panic(err)
 	}
 
-	var instance interface{}
-	load_test_json("draft4/benchmark/invalid.json", &instance)
+	instance := loadBenchmarkInstance("invalid.json")
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -46,3 +49,85 @@ func BenchmarkInvalid(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkValidateLines(b *testing.B) {
+	env := jsonschema.RootEnv.Clone()
+	schema, err := env.BuildSchema("", loadBenchmarkData("schema4.json"))
+	if err != nil {
+		panic(err)
+	}
+
+	instance := loadBenchmarkInstance("valid.json")
+	line, err := json.Marshal(instance)
+	if err != nil {
+		panic(err)
+	}
+
+	var input bytes.Buffer
+	for i := 0; i < 1000; i++ {
+		input.Write(line)
+		input.WriteByte('\n')
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		err := schema.ValidateLines(bytes.NewReader(input.Bytes()), 0, func(r *jsonschema.LineResult) error {
+			return r.Err
+		})
+		if err != nil {
+			b.Fatalf("error=%s", err)
+		}
+	}
+}
+
+// BenchmarkCompiledValid and BenchmarkCompiledInvalid run the functions
+// generated by GoCompiler for the schema of BenchmarkValid (see
+// compiled_benchmark_test.go) to compare them with the interpreter.
+func BenchmarkCompiledValid(b *testing.B) {
+	instance := loadBenchmarkInstance("valid.json")
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		err := ValidateBenchmark(instance)
+		if err != nil {
+			b.Fatalf("error=%s", err)
+		}
+	}
+}
+
+func BenchmarkCompiledInvalid(b *testing.B) {
+	instance := loadBenchmarkInstance("invalid.json")
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		err := ValidateBenchmark(instance)
+		if err == nil {
+			b.Fatalf("error=%s", "expected an error")
+		}
+	}
+}
+
+func loadBenchmarkData(name string) []byte {
+	data, err := ioutil.ReadFile("testdata/draft4/benchmark/" + name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func loadBenchmarkInstance(name string) interface{} {
+	var x interface{}
+	dec := json.NewDecoder(bytes.NewReader(loadBenchmarkData(name)))
+	dec.UseNumber()
+	err := dec.Decode(&x)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
@@ -8,15 +8,23 @@ import (
 )
 
 type Builder interface {
-	Build(pointer string, v map[string]interface{}) (*Schema, error)
+	Build(pointer string, v interface{}) (*Schema, error)
+	Reference(ref string) (*Schema, error)
 	GetFormatValidator(name string) FormatValidator
 	GetKeyword(s string) (interface{}, bool)
 }
 
 type builder struct {
 	env        *Env
+	dialect    *dialect
 	stack      []builderStackFrame
 	references map[string]*Schema
+
+	// keyword is the keyword of the validator which is being set up.
+	keyword string
+
+	// base is the URI of the document when it has no id.
+	base *url.URL
 }
 
 type builderStackFrame struct {
@@ -24,27 +32,47 @@ type builderStackFrame struct {
 	keywords map[string]bool
 }
 
-func newBuilder(env *Env) *builder {
+func newBuilder(env *Env, dialect *dialect) *builder {
 	return &builder{
 		env:        env,
+		dialect:    dialect,
 		references: map[string]*Schema{},
 		stack:      make([]builderStackFrame, 0, 1024),
 	}
 }
 
 func (b *builder) GetFormatValidator(name string) FormatValidator {
-	return b.env.formats[name]
+	return b.dialect.formats[name]
 }
 
-func (b *builder) Build(pointer string, v map[string]interface{}) (*Schema, error) {
+func (b *builder) Build(pointer string, x interface{}) (*Schema, error) {
 	var (
 		order      []int
 		validators map[int]Validator
+		keywords   map[int]string
 		schema     = &Schema{}
 		inlineId   *url.URL
 		base       *url.URL
+		v          map[string]interface{}
 	)
 
+	switch y := x.(type) {
+	case map[string]interface{}:
+		v = y
+	case bool:
+		// boolean schemas have no keywords (see below)
+	default:
+		return nil, fmt.Errorf("invalid schema: %#v", x)
+	}
+
+	// embedded schemas may declare their own dialect
+	if s, ok := v["$schema"].(string); ok && len(b.stack) > 0 {
+		if d, found := b.env.dialects[normalizeRef(s)]; found && d != b.dialect {
+			defer func(d *dialect) { b.dialect = d }(b.dialect)
+			b.dialect = d
+		}
+	}
+
 	// resolve the id
 	{
 		var (
@@ -52,11 +80,16 @@ func (b *builder) Build(pointer string, v map[string]interface{}) (*Schema, erro
 			err error
 		)
 
-		if l := len(b.stack); l > 0 {
+		if l := len(b.stack); l == 0 {
+			base = b.base
+		} else {
 			base = b.stack[l-1].schema.Id
+			schema.parent = b.stack[l-1].schema
+			schema.pointer = pointer
+			schema.parent.addChild(pointer, schema)
 		}
 
-		if x, ok := v["id"].(string); ok && x != "" {
+		if x, ok := v[b.dialect.idKeyword].(string); ok && x != "" {
 			id, err = url.Parse(x)
 			if err != nil {
 				return nil, err
@@ -88,6 +121,12 @@ func (b *builder) Build(pointer string, v map[string]interface{}) (*Schema, erro
 		schema.Id = id
 		b.references[normalizeRef(schema.Id.String())] = schema
 		b.references[normalizeRef(inlineId.String())] = schema
+
+		if l := len(b.stack); l == 0 || (id != inlineId && id.Fragment == "") {
+			schema.resource = schema
+		} else {
+			schema.resource = b.stack[l-1].schema.resource
+		}
 	}
 
 	if len(b.stack) >= 1024 {
@@ -109,7 +148,25 @@ func (b *builder) Build(pointer string, v map[string]interface{}) (*Schema, erro
 		root.Subschemas[inlineId.Fragment] = frame.schema
 	}
 
-	if refstr, ok := isRef(v); ok {
+	if y, ok := x.(bool); ok {
+		schema.Bool = &y
+		if !y {
+			schema.Validators = []Validator{&falseValidator{}}
+			schema.keywords = []string{""}
+		}
+		return schema, nil
+	}
+
+	err := b.registerAnchors(schema, v)
+	if err != nil {
+		return nil, err
+	}
+
+	// dialects which don't know the `$ref` keyword use the draft-04
+	// semantics where $ref overrides all other keywords.
+	_, refKeyword := b.dialect.keyword("$ref")
+
+	if refstr, ok := isRef(v); ok && !refKeyword {
 		ref, err := url.Parse(refstr)
 		if err != nil {
 			return nil, err
@@ -122,12 +179,10 @@ func (b *builder) Build(pointer string, v map[string]interface{}) (*Schema, erro
 		schema.Ref = ref
 
 		for k, x := range v {
-			if k != "$ref" {
-				if y, ok := x.(map[string]interface{}); ok && y != nil {
-					_, err := b.Build("/"+escapeJSONPointer(k), y)
-					if err != nil {
-						return nil, err
-					}
+			if k != "$ref" && !b.dialect.isExtension(k) && isSchema(x) {
+				_, err := b.Build("/"+escapeJSONPointer(k), x)
+				if err != nil {
+					return nil, err
 				}
 			}
 		}
@@ -136,38 +191,52 @@ func (b *builder) Build(pointer string, v map[string]interface{}) (*Schema, erro
 	}
 
 	validators = map[int]Validator{}
+	keywords = map[int]string{}
 	schema.Definition = v
 
 	var ready = map[*validator]bool{}
 	for k := range v {
-		validatorDef, found := b.env.validators[k]
+		validatorDef, found := b.dialect.keyword(k)
 		if !found {
 			continue
 		}
+		frame.keywords[k] = true
 		if ready[validatorDef] {
 			continue
 		}
 		ready[validatorDef] = true
 
-		frame.keywords[k] = true
+		keyword := validatorDef.keywords[0]
+		for _, k := range validatorDef.keywords {
+			if _, found := v[k]; found {
+				keyword = k
+				break
+			}
+		}
 
 		validator := reflect.New(validatorDef.prototype).Interface().(Validator)
+		outer := b.keyword
+		b.keyword = keyword
 		err := validator.Setup(b)
+		b.keyword = outer
 		if err != nil {
 			return nil, err
 		}
 
 		order = append(order, validatorDef.priority)
 		validators[validatorDef.priority] = validator
+		keywords[validatorDef.priority] = keyword
+
+		if _, ok := validator.(evaluationDependant); ok {
+			schema.tracksEvaluation = true
+		}
 	}
 
 	for k, x := range v {
-		if !frame.keywords[k] {
-			if y, ok := x.(map[string]interface{}); ok && y != nil {
-				_, err := b.Build("/"+escapeJSONPointer(k), y)
-				if err != nil {
-					return nil, err
-				}
+		if !frame.keywords[k] && !b.dialect.isExtension(k) && isSchema(x) {
+			_, err := b.Build("/"+escapeJSONPointer(k), x)
+			if err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -176,8 +245,128 @@ func (b *builder) Build(pointer string, v map[string]interface{}) (*Schema, erro
 
 	for _, i := range order {
 		schema.Validators = append(schema.Validators, validators[i])
+		schema.keywords = append(schema.keywords, keywords[i])
+	}
+
+	return schema, nil
+}
+
+// buildDocument builds the schemas in a document which is not a schema
+// itself (like an OpenAPI document). The definitions are keyed by their JSON
+// pointer in the document which is identified by id.
+func (b *builder) buildDocument(id string, defs map[string]interface{}) (map[string]*Schema, error) {
+	u, err := url.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &Schema{Id: u, Subschemas: map[string]*Schema{}}
+	root.resource = root
+	b.references[normalizeRef(u.String())] = root
+
+	b.stack = append(b.stack, builderStackFrame{
+		schema:   root,
+		keywords: map[string]bool{},
+	})
+	defer func() { b.stack = b.stack[:len(b.stack)-1] }()
+
+	pointers := make([]string, 0, len(defs))
+	for pointer := range defs {
+		pointers = append(pointers, pointer)
 	}
+	sort.Strings(pointers)
+
+	schemas := make(map[string]*Schema, len(defs))
+	for _, pointer := range pointers {
+		schema, err := b.Build(pointer, defs[pointer])
+		if err != nil {
+			return nil, err
+		}
+		schemas[pointer] = schema
+	}
+
+	return schemas, nil
+}
+
+// registerAnchors makes the schema addressable through the plain name
+// fragments defined by the `$anchor`, `$dynamicAnchor` and
+// `$recursiveAnchor` keywords.
+func (b *builder) registerAnchors(schema *Schema, v map[string]interface{}) error {
+	var (
+		resource = schema.resource
+		root     = b.stack[0].schema
+	)
+
+	for _, k := range []string{"$anchor", "$dynamicAnchor"} {
+		if _, found := b.dialect.keyword(k); !found {
+			continue
+		}
+
+		x, found := v[k]
+		if !found {
+			continue
+		}
+
+		name, ok := x.(string)
+		if !ok || name == "" {
+			return fmt.Errorf("invalid '%s' definition: %#v", k, x)
+		}
 
+		anchor := resolveRef(resource.Id, &url.URL{Fragment: name})
+		b.references[normalizeRef(anchor.String())] = schema
+		if resource == root {
+			root.Subschemas[name] = schema
+		}
+
+		if k == "$dynamicAnchor" {
+			resource.addDynamicAnchor(name, schema)
+		}
+	}
+
+	if _, found := b.dialect.keyword("$recursiveAnchor"); found {
+		if x, found := v["$recursiveAnchor"]; found {
+			y, ok := x.(bool)
+			if !ok {
+				return fmt.Errorf("invalid '$recursiveAnchor' definition: %#v", x)
+			}
+			if y && resource == schema {
+				resource.addDynamicAnchor("", schema)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Reference returns a schema which refers to the schema identified by ref
+// (relative to the schema which is currently being build). The reference is
+// identified by the keyword which makes it (like `$dynamicRef`) and it is
+// resolved after all schemas are build.
+func (b *builder) Reference(ref string) (*Schema, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	token := "/$ref"
+	if b.keyword != "" {
+		token = "/" + escapeJSONPointer(b.keyword)
+	}
+
+	var id *url.URL
+	if l := len(b.stack); l > 0 {
+		base := b.stack[l-1].schema.Id
+		u = resolveRef(base, u)
+		id = resolveRef(base, &url.URL{Fragment: base.Fragment + token})
+	} else {
+		id = &url.URL{Fragment: token}
+	}
+
+	schema := &Schema{Id: id, Ref: u}
+	if l := len(b.stack); l > 0 {
+		schema.parent = b.stack[l-1].schema
+	}
+	b.references[normalizeRef(id.String())] = schema
 	return schema, nil
 }
 
@@ -0,0 +1,191 @@
+// Command jsonschema-gen generates Go types from JSON schemas and from the
+// schemas of OpenAPI documents.
+//
+//	jsonschema-gen [-package name] [-o file] [-type name] [-validate file] schema.json...
+//
+// Each schema becomes a type named after its file (or -type when there is a
+// single schema) and its definitions become named types. References to
+// other files and to http(s) URLs are resolved. With -validate a function
+// which validates a value against each schema (like ValidateName
+// for the type Name) is written to another file of the package.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fd/jsonschema"
+)
+
+func main() {
+	var (
+		pkg      = flag.String("package", "schema", "name of the generated package")
+		output   = flag.String("o", "", "output file (default stdout)")
+		typeName = flag.String("type", "", "name of the type of the schema (with a single schema)")
+		validate = flag.String("validate", "", "output file of the validation functions")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: jsonschema-gen [flags] schema.json...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 || (*typeName != "" && flag.NArg() > 1) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	g := jsonschema.NewGoGenerator(*pkg)
+	c := jsonschema.NewGoCompiler(*pkg)
+
+	err := generate(g, c, *typeName, flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jsonschema-gen: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *validate != "" {
+		src, err := c.Generate()
+		if err == nil {
+			err = ioutil.WriteFile(*validate, src, 0644)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "jsonschema-gen: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	src, err := g.Generate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jsonschema-gen: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+
+	err = ioutil.WriteFile(*output, src, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jsonschema-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(g *jsonschema.GoGenerator, c *jsonschema.GoCompiler, typeName string, paths []string) error {
+	env := jsonschema.RootEnv.Clone()
+	env.Transport = &transport{}
+
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(abs)
+		if err != nil {
+			return err
+		}
+
+		var doc map[string]interface{}
+		err = json.Unmarshal(data, &doc)
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+
+		id := (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+
+		if _, found := doc["openapi"]; found {
+			err = addOpenAPI(env, g, c, id, data)
+		} else if _, found := doc["swagger"]; found {
+			err = addOpenAPI(env, g, c, id, data)
+		} else {
+			err = addSchema(env, g, c, id, typeName, path, doc, data)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+	}
+
+	return nil
+}
+
+func addSchema(env *jsonschema.Env, g *jsonschema.GoGenerator, c *jsonschema.GoCompiler, id, typeName, path string, doc map[string]interface{}, data []byte) error {
+	// a schema with its own id must be registered with that id
+	if _, found := doc["$id"]; found {
+		id = ""
+	} else if _, found := doc["id"]; found {
+		id = ""
+	}
+
+	schema, err := env.RegisterSchema(id, data)
+	if err != nil {
+		return err
+	}
+
+	if typeName == "" {
+		typeName = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	g.AddType(typeName, schema)
+	c.AddFunc("validate-"+typeName, schema)
+	return nil
+}
+
+func addOpenAPI(env *jsonschema.Env, g *jsonschema.GoGenerator, c *jsonschema.GoCompiler, id string, data []byte) error {
+	doc, err := env.LoadOpenAPI(id, data)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(doc.Schemas))
+	for name := range doc.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		g.AddType(name, doc.Schemas[name])
+		c.AddFunc("validate-"+name, doc.Schemas[name])
+	}
+	return nil
+}
+
+// transport loads the referenced schemas from files and http(s) URLs.
+type transport struct{}
+
+func (t *transport) Get(rawurl string) ([]byte, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "file":
+		return ioutil.ReadFile(filepath.FromSlash(u.Path))
+
+	case "http", "https":
+		resp, err := http.Get(rawurl)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", rawurl, resp.Status)
+		}
+		return ioutil.ReadAll(resp.Body)
+
+	default:
+		return nil, fmt.Errorf("unsupported schema URL: %s", rawurl)
+	}
+}
@@ -2,10 +2,36 @@ package jsonschema
 
 import (
 	"fmt"
+	"strconv"
 )
 
 type Context struct {
 	stack []contextStackFrame
+
+	// output is set when the evaluation is recorded for an OutputFormat
+	// (see Schema.ValidateOutput).
+	output bool
+	result *OutputUnit
+
+	// defaults is set by WithDefaults.
+	defaults bool
+
+	// coerce is set by WithTypeCoercion and coerceArrays by
+	// WithArrayCoercion.
+	coerce       bool
+	coerceArrays bool
+
+	// assertFormat is set by WithFormatAssertion.
+	assertFormat bool
+
+	// removeAdditional and onRemove are set by WithRemoveAdditional.
+	removeAdditional RemoveAdditionalMode
+	onRemove         func(instanceLocation string)
+
+	// inPlace is set when the instance is owned by the context (like the
+	// instance decoded by Schema.ValidateData) so it can be modified in
+	// place.
+	inPlace bool
 }
 
 type contextStackFrame struct {
@@ -13,17 +39,81 @@ type contextStackFrame struct {
 	value   interface{}
 	errors  []error
 	schema  *Schema
+
+	// owned is set when the value is a copy made by the context (see
+	// mutableMap and mutableSlice). Values owned by the caller are never
+	// modified. inPlace is set when the value and its members are owned by
+	// the context (see Context.inPlace) and the updates can't be undone
+	// (the errors of the frame are errors of the instance).
+	owned   bool
+	inPlace bool
+
+	// removed are the instance locations of the properties removed by
+	// WithRemoveAdditional. They are reported once the value of the frame
+	// is kept (by the parent frames up to the root frame).
+	removed []string
+
+	// via is the schema which was passed to the context (before following
+	// its references).
+	via *Schema
+
+	// the property name (or index when index >= 0) of the value in the
+	// value of the parent frame.
+	hasToken bool
+	property string
+	index    int
+
+	// keyword of the validator which is running.
+	keyword string
+
+	// location is created when an error is reported (see frameLocation).
+	location *frameLocation
+
+	// injected are the properties (and the indexes of the items) filled in
+	// by WithDefaults and inDefault is set when the value is (or is part
+	// of) a default filled in by WithDefaults.
+	injected  map[string]bool
+	inDefault bool
+
+	// output recording (see recordKeyword)
+	unit     *OutputUnit
+	units    []*OutputUnit
+	children []*OutputUnit
+
+	// evaluation tracking (see evaluationDependant)
+	tracking   bool
+	properties map[string]bool
+	items      int
+	itemSet    map[int]bool
+}
+
+// evaluationDependant is implemented by validators which depend on the
+// properties and items evaluated by the other keywords of their schema (and
+// by the in-place applicators of those keywords), like
+// `unevaluatedProperties`.
+type evaluationDependant interface {
+	Validator
+	dependsOnEvaluation()
 }
 
-func newContext() *Context {
-	return &Context{
+func newContext(options ...ValidateOption) *Context {
+	c := &Context{
 		stack: make([]contextStackFrame, 0, 8),
 	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
 }
 
 func (c *Context) Report(err error) {
 	l := len(c.stack)
 	frame := &c.stack[l-1]
+
+	if e, ok := err.(reportable); ok {
+		e.report(errorKeyword(frame.schema, err, frame.keyword), frame.value, frame.schema, c.frameLocation(l-1), "", "")
+	}
+
 	frame.errors = append(frame.errors, err)
 }
 
@@ -31,6 +121,82 @@ func (c *Context) UpdateValue(x interface{}) {
 	l := len(c.stack)
 	frame := &c.stack[l-1]
 	frame.value = x
+	frame.owned = false
+}
+
+// setProperty sets the property k of the current value to x (unless it is
+// already x).
+func (c *Context) setProperty(k string, x interface{}) {
+	y, ok := c.stack[len(c.stack)-1].value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if m, found := y[k]; found && sameValue(m, x) {
+		return
+	}
+	c.mutableMap()[k] = x
+}
+
+// setItem sets the item i of the current value to x (unless it is already
+// x).
+func (c *Context) setItem(i int, x interface{}) {
+	y, ok := c.stack[len(c.stack)-1].value.([]interface{})
+	if !ok || i >= len(y) || sameValue(y[i], x) {
+		return
+	}
+	c.mutableSlice()[i] = x
+}
+
+// removeProperty deletes the property k from the current value (see
+// WithRemoveAdditional).
+func (c *Context) removeProperty(k string) {
+	y, ok := c.stack[len(c.stack)-1].value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, found := y[k]; !found {
+		return
+	}
+
+	delete(c.mutableMap(), k)
+	if c.onRemove != nil {
+		frame := &c.stack[len(c.stack)-1]
+		frame.removed = append(frame.removed, c.instanceLocation(len(c.stack)-1)+"/"+escapeJSONPointer(k))
+	}
+}
+
+// mutableMap returns the current value (which must be an object) after
+// replacing it by a copy when it is owned by the caller.
+func (c *Context) mutableMap() map[string]interface{} {
+	frame := &c.stack[len(c.stack)-1]
+	y := frame.value.(map[string]interface{})
+	if frame.owned || frame.inPlace {
+		return y
+	}
+
+	z := make(map[string]interface{}, len(y))
+	for k, m := range y {
+		z[k] = m
+	}
+	frame.value = z
+	frame.owned = true
+	return z
+}
+
+// mutableSlice returns the current value (which must be an array) after
+// replacing it by a copy when it is owned by the caller.
+func (c *Context) mutableSlice() []interface{} {
+	frame := &c.stack[len(c.stack)-1]
+	y := frame.value.([]interface{})
+	if frame.owned || frame.inPlace {
+		return y
+	}
+
+	z := make([]interface{}, len(y))
+	copy(z, y)
+	frame.value = z
+	frame.owned = true
+	return z
 }
 
 func (c *Context) CurrentSchema() *Schema {
@@ -39,7 +205,42 @@ func (c *Context) CurrentSchema() *Schema {
 	return frame.schema
 }
 
+// ValidateValueWith validates a value other than the current value, where
+// the value has no location in the current value (like a property name).
+// Use ValidatePropertyWith and ValidateItemWith for the members of the
+// current value.
 func (c *Context) ValidateValueWith(x interface{}, schema *Schema) (interface{}, error) {
+	if len(c.stack) == 0 {
+		// accept any Go value (like structs and []string) as the instance
+		return c.validateValueWith(adaptValue(x), schema, false, "", -1, c.inPlace)
+	}
+	return c.validateValueWith(x, schema, false, "", -1, false)
+}
+
+// ValidatePropertyWith validates the property k of the current value.
+func (c *Context) ValidatePropertyWith(k string, x interface{}, schema *Schema) (interface{}, error) {
+	return c.validateValueWith(x, schema, true, k, -1, c.stack[len(c.stack)-1].inPlace)
+}
+
+// ValidateItemWith validates the item at index i of the current value.
+func (c *Context) ValidateItemWith(i int, x interface{}, schema *Schema) (interface{}, error) {
+	return c.validateValueWith(x, schema, true, "", i, c.stack[len(c.stack)-1].inPlace)
+}
+
+// checkItemWith validates the item at index i of the current value without
+// keeping any of the updates (like for `contains`).
+func (c *Context) checkItemWith(i int, x interface{}, schema *Schema) error {
+	l := len(c.stack)
+	n := len(c.stack[l-1].removed)
+	_, err := c.validateValueWith(x, schema, true, "", i, false)
+	c.stack[l-1].removed = c.stack[l-1].removed[:n]
+	return err
+}
+
+// validateValueWith validates x in a new frame. The value is updated in
+// place when inPlace is set; the removals of a member of the current value
+// are kept with its value (when it is valid).
+func (c *Context) validateValueWith(x interface{}, schema *Schema, hasToken bool, property string, index int, inPlace bool) (interface{}, error) {
 	l := len(c.stack)
 
 	if l == cap(c.stack) {
@@ -48,12 +249,12 @@ func (c *Context) ValidateValueWith(x interface{}, schema *Schema) (interface{},
 		c.stack = tmp
 	}
 
-	if schema.RefSchema != nil {
-		return c.ValidateValueWith(x, schema.RefSchema)
+	via := schema
+	for schema.RefSchema != nil {
+		schema = schema.RefSchema
 	}
 
 	var (
-		err         error
 		parentFrame *contextStackFrame
 		valueId     = 0
 	)
@@ -65,18 +266,35 @@ func (c *Context) ValidateValueWith(x interface{}, schema *Schema) (interface{},
 
 	// push stack frame
 	c.stack = append(c.stack, contextStackFrame{
-		valueId: valueId,
-		value:   x,
-		schema:  schema,
+		valueId:  valueId,
+		value:    x,
+		schema:   schema,
+		via:      via,
+		hasToken: hasToken,
+		property: property,
+		index:    index,
+		inPlace:  inPlace,
+		tracking: schema.tracksEvaluation,
 	})
 
-	for _, validator := range schema.Validators {
-		validator.Validate(c.stack[l].value, c)
+	if c.defaults && parentFrame != nil {
+		frame := &c.stack[l]
+		frame.inDefault = parentFrame.inDefault
+		if !frame.inDefault && parentFrame.injected != nil {
+			frame.inDefault = parentFrame.injected[frame.instanceToken()]
+		}
 	}
 
+	err := c.evaluate()
+
 	frame := &c.stack[l]
-	if len(frame.errors) > 0 {
-		err = &ErrInvalidInstance{schema, frame.errors}
+	switch {
+	case l == 0:
+		for _, location := range frame.removed {
+			c.onRemove(location)
+		}
+	case hasToken && err == nil:
+		c.stack[l-1].removed = append(c.stack[l-1].removed, frame.removed...)
 	}
 
 	// pop stack frame
@@ -84,7 +302,17 @@ func (c *Context) ValidateValueWith(x interface{}, schema *Schema) (interface{},
 	return frame.value, err
 }
 
+// ValidateSelfWith validates the current value against schema. The updates
+// made by schema are only kept when the current value is valid against it
+// (like for the subschemas of `anyOf`).
 func (c *Context) ValidateSelfWith(schema *Schema) (interface{}, error) {
+	return c.validateSelfWith(schema, false)
+}
+
+// validateSelfWith validates the current value against schema. When inPlace
+// is set the updates are made to the current value (for the subschemas
+// which must be valid, like the subschemas of `allOf`).
+func (c *Context) validateSelfWith(schema *Schema, inPlace bool) (interface{}, error) {
 	l := len(c.stack)
 
 	if l == cap(c.stack) {
@@ -97,12 +325,12 @@ func (c *Context) ValidateSelfWith(schema *Schema) (interface{}, error) {
 		return nil, fmt.Errorf("ValidateWith() cannot be a root frame")
 	}
 
-	if schema.RefSchema != nil {
-		return c.ValidateSelfWith(schema.RefSchema)
+	via := schema
+	for schema.RefSchema != nil {
+		schema = schema.RefSchema
 	}
 
 	var (
-		err         error
 		parentFrame = &c.stack[l-1]
 	)
 
@@ -118,18 +346,30 @@ func (c *Context) ValidateSelfWith(schema *Schema) (interface{}, error) {
 
 	// push stack frame
 	c.stack = append(c.stack, contextStackFrame{
-		valueId: parentFrame.valueId,
-		value:   parentFrame.value,
-		schema:  schema,
+		valueId:   parentFrame.valueId,
+		value:     parentFrame.value,
+		owned:     inPlace && parentFrame.owned,
+		inPlace:   inPlace && parentFrame.inPlace,
+		schema:    schema,
+		via:       via,
+		tracking:  parentFrame.tracking || schema.tracksEvaluation,
+		injected:  parentFrame.injected,
+		inDefault: parentFrame.inDefault,
 	})
 
-	for _, validator := range schema.Validators {
-		validator.Validate(c.stack[l].value, c)
-	}
+	err := c.evaluate()
 
 	frame := &c.stack[l]
-	if len(frame.errors) > 0 {
-		err = &ErrInvalidInstance{schema, frame.errors}
+	if err == nil {
+		// the value is the value of the parent frame
+		c.stack[l-1].value = frame.value
+		c.stack[l-1].owned = frame.owned
+		c.stack[l-1].injected = frame.injected
+		c.stack[l-1].removed = append(c.stack[l-1].removed, frame.removed...)
+
+		if c.stack[l-1].tracking {
+			c.stack[l-1].mergeEvaluation(frame)
+		}
 	}
 
 	// pop stack frame
@@ -137,6 +377,272 @@ func (c *Context) ValidateSelfWith(schema *Schema) (interface{}, error) {
 	return frame.value, err
 }
 
+// evaluate runs the validators of the schema of the top frame.
+func (c *Context) evaluate() error {
+	var (
+		l      = len(c.stack) - 1
+		schema = c.stack[l].schema
+	)
+
+	if c.output {
+		c.stack[l].unit = c.newSchemaUnit(l)
+	}
+
+	if c.defaults {
+		for _, validator := range schema.Validators {
+			if v, ok := validator.(defaultsInjector); ok {
+				v.injectDefaults(c.stack[l].value, c)
+			}
+		}
+	}
+
+	for i, validator := range schema.Validators {
+		var (
+			n       = len(c.stack[l].errors)
+			keyword string
+		)
+
+		if i < len(schema.keywords) {
+			keyword = schema.keywords[i]
+		}
+		c.stack[l].keyword = keyword
+
+		validator.Validate(c.stack[l].value, c)
+
+		if c.output {
+			c.recordKeyword(l, n)
+		}
+	}
+
+	var (
+		err   error
+		frame = &c.stack[l]
+	)
+
+	if len(frame.errors) > 0 {
+		e := &ErrInvalidInstance{Errors: frame.errors}
+		e.report("", frame.value, schema, c.frameLocation(l), "", "")
+		err = e
+	}
+
+	if c.output {
+		c.recordSchema(l)
+	}
+
+	return err
+}
+
+// instanceLocation returns the JSON pointer to the value of frame l.
+func (c *Context) instanceLocation(l int) string {
+	var location string
+	for i := 0; i <= l; i++ {
+		location += c.stack[i].instanceToken()
+	}
+	return location
+}
+
+// instanceToken returns the location of the value of the frame in the value
+// of its parent frame.
+func (f *contextStackFrame) instanceToken() string {
+	return instanceToken(f.hasToken, f.property, f.index)
+}
+
+func instanceToken(hasToken bool, property string, index int) string {
+	switch {
+	case !hasToken:
+		return ""
+	case index >= 0:
+		return "/" + strconv.Itoa(index)
+	default:
+		return "/" + escapeJSONPointer(property)
+	}
+}
+
+// relativeKeywordLocation returns the location of the schema of frame l
+// relative to the schema of its parent frame.
+func (c *Context) relativeKeywordLocation(l int) string {
+	frame := &c.stack[l]
+	if l == 0 {
+		return relativeKeywordLocation(frame.via, nil, "")
+	}
+	parent := &c.stack[l-1]
+	return relativeKeywordLocation(frame.via, parent.schema, parent.keyword)
+}
+
+// relativeKeywordLocation returns the location of via relative to the schema
+// parent (nil for the root frame) where parentKeyword is the keyword which
+// applies via.
+func relativeKeywordLocation(via, parent *Schema, parentKeyword string) string {
+	var location string
+
+	if parent != nil {
+		if via.parent == parent && via.pointer != "" {
+			location = via.pointer
+		} else {
+			location = joinKeywordLocation("", parentKeyword)
+		}
+	}
+
+	// references which are not created by a keyword (like the `$ref` of
+	// draft-04) add their own `$ref` to the location.
+	for s := via; s.RefSchema != nil; s = s.RefSchema {
+		if s.pointer != "" || s.parent == nil {
+			location += "/$ref"
+		}
+	}
+
+	return location
+}
+
+// frameLocation is the location of a frame: the location of its value in
+// the instance and of its schema (through the references which were
+// followed). It outlives the frame so the JSON pointers of the errors are
+// only built when they are asked for (most errors, like the errors of the
+// failed branches of an `anyOf`, are never located).
+type frameLocation struct {
+	parent *frameLocation
+
+	hasToken bool
+	property string
+	index    int
+
+	via           *Schema
+	parentSchema  *Schema
+	parentKeyword string
+}
+
+// frameLocation returns the location of frame l.
+func (c *Context) frameLocation(l int) *frameLocation {
+	frame := &c.stack[l]
+	if frame.location != nil {
+		return frame.location
+	}
+
+	location := &frameLocation{
+		hasToken: frame.hasToken,
+		property: frame.property,
+		index:    frame.index,
+		via:      frame.via,
+	}
+	if l > 0 {
+		// the keyword of the parent frame doesn't change while frame l is
+		// on the stack
+		location.parent = c.frameLocation(l - 1)
+		location.parentSchema = c.stack[l-1].schema
+		location.parentKeyword = c.stack[l-1].keyword
+	}
+
+	frame.location = location
+	return location
+}
+
+func (f *frameLocation) instanceLocation() string {
+	var location string
+	for ; f != nil; f = f.parent {
+		location = instanceToken(f.hasToken, f.property, f.index) + location
+	}
+	return location
+}
+
+func (f *frameLocation) keywordLocation() string {
+	var location string
+	for ; f != nil; f = f.parent {
+		location = relativeKeywordLocation(f.via, f.parentSchema, f.parentKeyword) + location
+	}
+	return location
+}
+
+func joinKeywordLocation(location, keyword string) string {
+	if keyword == "" {
+		return location
+	}
+	return location + "/" + escapeJSONPointer(keyword)
+}
+
+// dynamicScope returns the outermost schema in the dynamic scope which
+// defines the dynamic anchor name.
+func (c *Context) dynamicScope(name string) *Schema {
+	for i := range c.stack {
+		resource := c.stack[i].schema.resource
+		if resource == nil {
+			continue
+		}
+		if target, found := resource.dynamicAnchors[name]; found {
+			return target
+		}
+	}
+	return nil
+}
+
+func (c *Context) tracking() bool {
+	l := len(c.stack)
+	return c.stack[l-1].tracking
+}
+
+func (c *Context) markPropertyEvaluated(k string) {
+	l := len(c.stack)
+	frame := &c.stack[l-1]
+	if !frame.tracking {
+		return
+	}
+	if frame.properties == nil {
+		frame.properties = map[string]bool{}
+	}
+	frame.properties[k] = true
+}
+
+// markItemsEvaluated marks the first n items as evaluated.
+func (c *Context) markItemsEvaluated(n int) {
+	l := len(c.stack)
+	frame := &c.stack[l-1]
+	if frame.items < n {
+		frame.items = n
+	}
+}
+
+func (c *Context) markItemEvaluated(i int) {
+	l := len(c.stack)
+	frame := &c.stack[l-1]
+	if !frame.tracking {
+		return
+	}
+	if frame.itemSet == nil {
+		frame.itemSet = map[int]bool{}
+	}
+	frame.itemSet[i] = true
+}
+
+func (c *Context) isPropertyEvaluated(k string) bool {
+	l := len(c.stack)
+	return c.stack[l-1].properties[k]
+}
+
+func (c *Context) isItemEvaluated(i int) bool {
+	l := len(c.stack)
+	frame := &c.stack[l-1]
+	return i < frame.items || frame.itemSet[i]
+}
+
+func (f *contextStackFrame) mergeEvaluation(g *contextStackFrame) {
+	if len(g.properties) > 0 && f.properties == nil {
+		f.properties = make(map[string]bool, len(g.properties))
+	}
+	for k := range g.properties {
+		f.properties[k] = true
+	}
+
+	if f.items < g.items {
+		f.items = g.items
+	}
+
+	if len(g.itemSet) > 0 && f.itemSet == nil {
+		f.itemSet = make(map[int]bool, len(g.itemSet))
+	}
+	for i := range g.itemSet {
+		f.itemSet[i] = true
+	}
+}
+
 type PrimitiveType string
 
 const (
@@ -0,0 +1,210 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContractTransport is an http.RoundTripper which checks the JSON bodies of
+// the responses against the schemas of their route (see Route.Responses),
+// like the routes of an OpenAPI document (see OpenAPIRoutes) or routes with
+// the schemas registered in an Env (see Env.Schema). The responses are
+// returned unchanged; the violations are collected for the test to check.
+//
+// The schema of a response is the schema of its status code (or of its
+// range, like "2XX", or "default") and media type (or of a media range,
+// like "application/*"). Responses with a status which isn't documented
+// (and without a "default" response) are violations unless the route has
+// no responses at all; responses of statuses without schemas aren't
+// checked.
+type ContractTransport struct {
+	// Transport makes the requests (http.DefaultTransport when nil).
+	Transport http.RoundTripper
+
+	Routes []*Route
+
+	mu         sync.Mutex
+	violations []*ContractViolation
+}
+
+// ContractViolation is a response which doesn't match the schema of its
+// route.
+type ContractViolation struct {
+	Request     *http.Request
+	Status      int
+	ContentType string
+
+	// Pointer is the location of the invalid value in the body.
+	Pointer string
+	Keyword string
+	Detail  string
+}
+
+func (v *ContractViolation) String() string {
+	return fmt.Sprintf("%s %s: %d %s at %q: %s", v.Request.Method, v.Request.URL, v.Status, v.ContentType, v.Pointer, v.Detail)
+}
+
+// ContractTester is implemented by *testing.T and *testing.B.
+type ContractTester interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// CheckTestServer makes the Client of server (an *httptest.Server) check
+// the responses and returns its ContractTransport.
+func CheckTestServer(server interface{ Client() *http.Client }, routes []*Route) *ContractTransport {
+	client := server.Client()
+	c := &ContractTransport{Transport: client.Transport, Routes: routes}
+	client.Transport = c
+	return c
+}
+
+// RoundTrip makes the request and checks the response.
+func (c *ContractTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	route, _ := matchRoute(c.Routes, req)
+	if route == nil {
+		return resp, nil
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	c.check(route, req, resp, data)
+	return resp, nil
+}
+
+// Violations returns the violations collected so far.
+func (c *ContractTransport) Violations() []*ContractViolation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*ContractViolation(nil), c.violations...)
+}
+
+// Check reports the violations collected so far as errors of t.
+func (c *ContractTransport) Check(t ContractTester) {
+	t.Helper()
+	for _, v := range c.Violations() {
+		t.Errorf("contract violation: %s", v)
+	}
+}
+
+func (c *ContractTransport) check(route *Route, req *http.Request, resp *http.Response, data []byte) {
+	contentType := resp.Header.Get("Content-Type")
+	violation := func(pointer, keyword, detail string) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.violations = append(c.violations, &ContractViolation{
+			Request:     req,
+			Status:      resp.StatusCode,
+			ContentType: contentType,
+			Pointer:     pointer,
+			Keyword:     keyword,
+			Detail:      detail,
+		})
+	}
+
+	content, found := responseContent(route.Responses, resp.StatusCode)
+	if !found {
+		if len(route.Responses) > 0 {
+			violation("", "", fmt.Sprintf("undocumented status %d", resp.StatusCode))
+		}
+		return
+	}
+	if len(content) == 0 {
+		return
+	}
+
+	if len(data) == 0 && contentType == "" {
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		violation("", "", fmt.Sprintf("invalid content type: %s", err))
+		return
+	}
+
+	schema, found := responseSchema(content, mediaType)
+	if !found {
+		violation("", "", fmt.Sprintf("undocumented media type %s", mediaType))
+		return
+	}
+	if schema == nil || !isJSONMediaType(mediaType) {
+		return
+	}
+
+	var x interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = dec.Decode(&x)
+	if err != nil {
+		violation("", "", fmt.Sprintf("invalid JSON: %s", err))
+		return
+	}
+
+	_, err = validateInPlace(schema, x, nil)
+	for _, err := range leafErrors(err) {
+		if e, ok := err.(Error); ok {
+			violation(e.InstanceLocation(), e.Keyword(), outputMessage(err))
+		} else if err != nil {
+			violation("", "", err.Error())
+		}
+	}
+}
+
+// responseContent returns the schemas (by media type) of the responses with
+// status. It returns false when status isn't documented.
+func responseContent(responses map[string]map[string]*Schema, status int) (map[string]*Schema, bool) {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		if content, found := responses[key]; found {
+			return content, true
+		}
+	}
+	return nil, false
+}
+
+// responseSchema returns the schema of mediaType. The media type without
+// parameters is matched by the exact media type first and then by the
+// media ranges (like "application/*" and "*/*").
+func responseSchema(content map[string]*Schema, mediaType string) (*Schema, bool) {
+	mediaType = strings.ToLower(mediaType)
+
+	candidates := []string{mediaType}
+	if i := strings.IndexByte(mediaType, '/'); i >= 0 {
+		candidates = append(candidates, mediaType[:i]+"/*")
+	}
+	candidates = append(candidates, "*/*")
+
+	for _, candidate := range candidates {
+		for key, schema := range content {
+			if k, _, err := mime.ParseMediaType(key); err == nil && strings.ToLower(k) == candidate {
+				return schema, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,457 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeError is returned by Schema.DecodeValid when a value of the (valid)
+// instance can't be stored in the target.
+type DecodeError struct {
+	Value interface{}
+	Type  reflect.Type
+
+	// Err is the error of a json.Unmarshaler or encoding.TextUnmarshaler.
+	Err error
+
+	instanceLocation string
+}
+
+// InstanceLocation returns the JSON pointer to the value in the instance.
+func (e *DecodeError) InstanceLocation() string { return e.instanceLocation }
+func (e *DecodeError) Unwrap() error            { return e.Err }
+
+func (e *DecodeError) Error() string {
+	location := e.instanceLocation
+	if location == "" {
+		location = "/"
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("cannot decode value at %q into %s: %s", location, e.Type, e.Err)
+	}
+	return fmt.Sprintf("cannot decode %#v at %q into %s", e.Value, location, e.Type)
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// DecodeValid validates the JSON document data and decodes the validated
+// instance (with the updates made by the validators, like the defaults of
+// WithDefaults) into out, which must be a non-nil pointer. Values are
+// decoded like encoding/json does; interface values receive the validated
+// instance as is.
+func (s *Schema) DecodeValid(data []byte, out interface{}, options ...ValidateOption) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(out)}
+	}
+
+	var v interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err := dec.Decode(&v)
+	if err != nil {
+		return err
+	}
+
+	v, err = validateInPlace(s, v, options)
+	if err != nil {
+		return err
+	}
+
+	return decodeValue(v, rv.Elem(), "")
+}
+
+// decodeValue stores x in v. location is the instance location of x.
+func decodeValue(x interface{}, v reflect.Value, location string) error {
+	if x == nil {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(x, v.Elem(), location)
+	}
+
+	mismatch := func() error {
+		return &DecodeError{Value: x, Type: v.Type(), instanceLocation: location}
+	}
+
+	if v.CanAddr() {
+		p := v.Addr()
+
+		if p.Type().Implements(jsonUnmarshalerType) {
+			data, err := json.Marshal(x)
+			if err == nil {
+				err = p.Interface().(json.Unmarshaler).UnmarshalJSON(data)
+			}
+			if err != nil {
+				return &DecodeError{Value: x, Type: v.Type(), Err: err, instanceLocation: location}
+			}
+			return nil
+		}
+
+		if s, ok := x.(string); ok && p.Type().Implements(textUnmarshalerType) {
+			err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+			if err != nil {
+				return &DecodeError{Value: x, Type: v.Type(), Err: err, instanceLocation: location}
+			}
+			return nil
+		}
+	}
+
+	if v.Kind() == reflect.Interface {
+		if v.NumMethod() > 0 {
+			return mismatch()
+		}
+		v.Set(reflect.ValueOf(x))
+		return nil
+	}
+
+	switch y := x.(type) {
+	case bool:
+		if v.Kind() != reflect.Bool {
+			return mismatch()
+		}
+		v.SetBool(y)
+
+	case string:
+		switch {
+		case v.Kind() == reflect.String:
+			v.SetString(y)
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			b, err := base64.StdEncoding.DecodeString(y)
+			if err != nil {
+				return &DecodeError{Value: x, Type: v.Type(), Err: err, instanceLocation: location}
+			}
+			v.SetBytes(b)
+		default:
+			return mismatch()
+		}
+
+	case json.Number, int64, float64:
+		if !decodeNumber(y, v) {
+			return mismatch()
+		}
+
+	case []interface{}:
+		switch v.Kind() {
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(v.Type(), len(y), len(y)))
+		case reflect.Array:
+			if len(y) > v.Len() {
+				return mismatch()
+			}
+			v.Set(reflect.Zero(v.Type()))
+		default:
+			return mismatch()
+		}
+		for i, item := range y {
+			err := decodeValue(item, v.Index(i), location+"/"+strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		switch v.Kind() {
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return mismatch()
+			}
+			if v.IsNil() {
+				v.Set(reflect.MakeMapWithSize(v.Type(), len(y)))
+			}
+			for k, m := range y {
+				elem := reflect.New(v.Type().Elem()).Elem()
+				err := decodeValue(m, elem, location+"/"+escapeJSONPointer(k))
+				if err != nil {
+					return err
+				}
+				v.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), elem)
+			}
+
+		case reflect.Struct:
+			fields := structFields(v.Type())
+			for k, m := range y {
+				field, found := lookupField(fields, k)
+				if !found {
+					continue
+				}
+				fv, err := fieldByIndex(v, field.index)
+				if err != nil {
+					return &DecodeError{Value: m, Type: v.Type(), Err: err, instanceLocation: location + "/" + escapeJSONPointer(k)}
+				}
+				if field.quoted {
+					err = decodeQuoted(m, fv, location+"/"+escapeJSONPointer(k))
+				} else {
+					err = decodeValue(m, fv, location+"/"+escapeJSONPointer(k))
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+		default:
+			return mismatch()
+		}
+
+	default:
+		return mismatch()
+	}
+
+	return nil
+}
+
+// decodeQuoted stores the JSON literal in the string x in v, like
+// encoding/json does for the fields with the `string` option. The option is
+// ignored for the fields which are not booleans, numbers or strings.
+func decodeQuoted(x interface{}, v reflect.Value, location string) error {
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+	default:
+		return decodeValue(x, v, location)
+	}
+
+	if x == nil {
+		return decodeValue(nil, v, location)
+	}
+
+	s, ok := x.(string)
+	if !ok {
+		err := fmt.Errorf("invalid use of ,string struct tag, trying to unmarshal unquoted value into %v", v.Type())
+		return &DecodeError{Value: x, Type: v.Type(), Err: err, instanceLocation: location}
+	}
+
+	var y interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	err := dec.Decode(&y)
+	if err == nil {
+		if _, err = dec.Token(); err == io.EOF {
+			err = nil
+		} else {
+			err = errors.New("unexpected data after the value")
+		}
+	}
+	if err == nil {
+		switch y.(type) {
+		case nil:
+		case string:
+			if t.Kind() != reflect.String {
+				err = errors.New("unexpected string")
+			}
+		case bool, json.Number:
+			if t.Kind() == reflect.String {
+				err = errors.New("expected a quoted string")
+			}
+		default:
+			err = errors.New("unexpected value")
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid use of ,string struct tag, trying to unmarshal %q into %v: %s", s, v.Type(), err)
+		return &DecodeError{Value: x, Type: v.Type(), Err: err, instanceLocation: location}
+	}
+
+	return decodeValue(y, v, location)
+}
+
+// decodeNumber stores the number x in v. It returns false when v is not a
+// number or when x doesn't fit in v.
+func decodeNumber(x interface{}, v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := toInt64(x)
+		if !ok || v.OverflowInt(i) {
+			return false
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, ok := toInt64(x)
+		if !ok || i < 0 || v.OverflowUint(uint64(i)) {
+			return false
+		}
+		v.SetUint(uint64(i))
+
+	case reflect.Float32, reflect.Float64:
+		f, ok, err := toFloat(x)
+		if !ok || err != nil || v.OverflowFloat(f) {
+			return false
+		}
+		v.SetFloat(f)
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func toInt64(x interface{}) (int64, bool) {
+	switch y := x.(type) {
+	case int64:
+		return y, true
+	case json.Number:
+		if i, err := y.Int64(); err == nil {
+			return i, true
+		}
+		f, err := y.Float64()
+		return int64(f), err == nil && f == math.Trunc(f) && math.Abs(f) < 1<<63
+	case float64:
+		return int64(y), y == math.Trunc(y) && math.Abs(y) < 1<<63
+	default:
+		return 0, false
+	}
+}
+
+// structField is a field of a struct (or of one of its embedded structs)
+// with the options of its `json` tag.
+type structField struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	tag       reflect.StructTag
+	omitEmpty bool
+	quoted    bool
+}
+
+// structFields returns the fields of t which can be decoded (by their JSON
+// name) including the fields of embedded structs. Shallower fields hide the
+// deeper fields with the same name.
+func structFields(t reflect.Type) []structField {
+	var (
+		fields  []structField
+		seen    = map[string]bool{}
+		visited = map[reflect.Type]bool{t: true}
+		queue   = []structField{{}}
+	)
+
+	for len(queue) > 0 {
+		var next []structField
+		var level []structField
+
+		for _, parent := range queue {
+			st := t
+			if len(parent.index) > 0 {
+				st = t.FieldByIndex(parent.index).Type
+				if st.Kind() == reflect.Ptr {
+					st = st.Elem()
+				}
+			}
+
+			for i := 0; i < st.NumField(); i++ {
+				f := st.Field(i)
+				index := append(append([]int{}, parent.index...), i)
+
+				tag := f.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				options := strings.Split(tag, ",")
+				name := options[0]
+
+				ft := f.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					if !visited[ft] {
+						visited[ft] = true
+						next = append(next, structField{index: index})
+					}
+					continue
+				}
+				if !f.IsExported() {
+					continue
+				}
+
+				if name == "" {
+					name = f.Name
+				}
+				field := structField{name: name, index: index, typ: f.Type, tag: f.Tag}
+				for _, option := range options[1:] {
+					switch option {
+					case "omitempty":
+						field.omitEmpty = true
+					case "string":
+						field.quoted = true
+					}
+				}
+				level = append(level, field)
+			}
+		}
+
+		for _, f := range level {
+			if !seen[f.name] {
+				fields = append(fields, f)
+			}
+		}
+		for _, f := range level {
+			seen[f.name] = true
+		}
+
+		queue = next
+	}
+
+	return fields
+}
+
+// lookupField finds the field for the property k. Like encoding/json it
+// prefers an exact match over a case-insensitive match.
+func lookupField(fields []structField, k string) (structField, bool) {
+	for _, f := range fields {
+		if f.name == k {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, k) {
+			return f, true
+		}
+	}
+	return structField{}, false
+}
+
+// fieldByIndex returns the field of v at index, allocating the embedded
+// struct pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct: %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
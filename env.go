@@ -4,16 +4,19 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"sort"
+	"strings"
 )
 
 type Env struct {
 	Transport Transport
 
-	schemas    map[string]*Schema
-	validators map[string]*validator
-	formats    map[string]FormatValidator
+	schemas      map[string]*Schema
+	vocabularies map[string]*Vocabulary
+	dialects     map[string]*dialect
+	dialect      *dialect
 }
 
 type Transport interface {
@@ -21,19 +24,59 @@ type Transport interface {
 }
 
 type validator struct {
+	// keywords are the keywords handled by the validator; the keyword it
+	// was registered with comes first.
 	keywords  []string
 	priority  int
 	prototype reflect.Type
 }
 
+// dialect is a set of keywords and formats selected by the `$schema`
+// keyword of a schema. The keywords are made up of those of the
+// vocabularies and those registered directly on the dialect.
+type dialect struct {
+	uri          string
+	idKeyword    string
+	vocabularies []*Vocabulary
+	validators   map[string]*validator
+	formats      map[string]FormatValidator
+
+	// extensionPrefix marks the keywords which are vendor extensions (like
+	// `x-` in OpenAPI). Their values are never build as schemas.
+	extensionPrefix string
+}
+
 func NewEnv() *Env {
 	return &Env{
-		schemas:    map[string]*Schema{},
-		validators: map[string]*validator{},
-		formats:    map[string]FormatValidator{},
+		schemas:      map[string]*Schema{},
+		vocabularies: map[string]*Vocabulary{},
+		dialects:     map[string]*dialect{},
+		dialect:      newDialect("http://json-schema.org/draft-04/schema#", "id"),
+	}
+}
+
+func newDialect(uri, idKeyword string, vocabularies ...*Vocabulary) *dialect {
+	return &dialect{
+		uri:          normalizeRef(uri),
+		idKeyword:    idKeyword,
+		vocabularies: vocabularies,
+		validators:   map[string]*validator{},
+		formats:      map[string]FormatValidator{},
 	}
 }
 
+func (d *dialect) keyword(key string) (*validator, bool) {
+	if v, found := d.validators[key]; found {
+		return v, true
+	}
+	for _, vocabulary := range d.vocabularies {
+		if v, found := vocabulary.validators[key]; found {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
 func (e *Env) Clone() *Env {
 
 	schemas := make(map[string]*Schema, len(e.schemas))
@@ -41,50 +84,181 @@ func (e *Env) Clone() *Env {
 		schemas[k] = v
 	}
 
-	validators := make(map[string]*validator, len(e.validators))
-	for k, v := range e.validators {
-		validators[k] = v
+	vocabularies := make(map[string]*Vocabulary, len(e.vocabularies))
+	for k, v := range e.vocabularies {
+		vocabularies[k] = v
 	}
 
-	formats := make(map[string]FormatValidator, len(e.formats))
-	for k, v := range e.formats {
-		formats[k] = v
+	clones := make(map[*dialect]*dialect, len(e.dialects)+1)
+	clones[e.dialect] = e.dialect.clone()
+
+	dialects := make(map[string]*dialect, len(e.dialects))
+	for k, v := range e.dialects {
+		if _, found := clones[v]; !found {
+			clones[v] = v.clone()
+		}
+		dialects[k] = clones[v]
 	}
 
 	return &Env{
 		e.Transport,
 		schemas,
-		validators,
-		formats,
+		vocabularies,
+		dialects,
+		clones[e.dialect],
 	}
 }
 
+func (d *dialect) clone() *dialect {
+	validators := make(map[string]*validator, len(d.validators))
+	for k, v := range d.validators {
+		validators[k] = v
+	}
+
+	formats := make(map[string]FormatValidator, len(d.formats))
+	for k, v := range d.formats {
+		formats[k] = v
+	}
+
+	return &dialect{d.uri, d.idKeyword, d.vocabularies, validators, formats, d.extensionPrefix}
+}
+
+func (d *dialect) isExtension(key string) bool {
+	return d.extensionPrefix != "" && strings.HasPrefix(key, d.extensionPrefix)
+}
+
 func (e *Env) RegisterKeyword(v Validator, priority int, key string, additionalKeys ...string) {
-	keys := append(additionalKeys, key)
-	sort.Strings(keys)
+	e.dialect.registerKeyword(v, priority, key, additionalKeys...)
+}
+
+func (d *dialect) registerKeyword(v Validator, priority int, key string, additionalKeys ...string) {
+	registerKeyword(d.validators, v, priority, key, additionalKeys...)
+}
+
+func (e *Env) RegisterFormat(key string, v FormatValidator) {
+	e.dialect.registerFormat(key, v)
+}
+
+func (d *dialect) registerFormat(key string, v FormatValidator) {
+	if _, found := d.formats[key]; found {
+		panic("format is already registered")
+	}
+	d.formats[key] = v
+}
+
+func (e *Env) RegisterVocabulary(v *Vocabulary) {
+	if _, found := e.vocabularies[v.uri]; found {
+		panic("vocabulary is already registered")
+	}
+	e.vocabularies[v.uri] = v
+}
+
+// RegisterDialect makes the dialect identified by uri available to the
+// schemas which declare it in their `$schema` keyword. Those schemas are
+// build with the keywords of the vocabularies and with the formats. The
+// metaschema is optional; when given it is registered as the schema of uri
+// and all schemas of the dialect are validated against it.
+func (e *Env) RegisterDialect(uri string, vocabularies []*Vocabulary, formats map[string]FormatValidator, metaschema []byte) error {
+	uri = normalizeRef(uri)
+	if _, found := e.dialects[uri]; found {
+		return fmt.Errorf("dialect is already registered: %s", uri)
+	}
+
+	idKeyword := e.dialect.idKeyword
+	if metaschema != nil {
+		var def map[string]interface{}
+
+		dec := json.NewDecoder(bytes.NewReader(metaschema))
+		dec.UseNumber()
+		err := dec.Decode(&def)
+		if err != nil {
+			return err
+		}
+
+		idKeyword = e.dialectOf(def).idKeyword
+	}
+
+	d := newDialect(uri, idKeyword, vocabularies...)
+	for k, v := range formats {
+		d.registerFormat(k, v)
+	}
+	e.registerDialect(d)
+
+	if metaschema != nil {
+		schema, err := e.RegisterSchema("", metaschema)
+		if err != nil {
+			delete(e.dialects, uri)
+			return err
+		}
 
-	for _, key := range keys {
-		if _, found := e.validators[key]; found {
-			panic("keyword is already registered")
+		if normalizeRef(schema.Id.String()) != uri {
+			delete(e.dialects, uri)
+			delete(e.schemas, normalizeRef(schema.Id.String()))
+			return fmt.Errorf("metaschema id did not match dialect (%q != %q)", schema.Id, uri)
 		}
 	}
 
-	rt := reflect.TypeOf(v)
-	if rt.Kind() != reflect.Ptr {
-		panic("Validator must be a pointer")
+	return nil
+}
+
+// DialectVocabulary returns a vocabulary with all the keywords of the
+// dialect identified by uri. It is used to register dialects which extend
+// another dialect.
+func (e *Env) DialectVocabulary(uri string) (*Vocabulary, bool) {
+	d, found := e.dialects[normalizeRef(uri)]
+	if !found {
+		return nil, false
 	}
 
-	validator := &validator{keys, priority, rt.Elem()}
-	for _, key := range keys {
-		e.validators[key] = validator
+	v := NewVocabulary(d.uri)
+	for k, x := range d.validators {
+		v.validators[k] = x
 	}
+	for _, vocabulary := range d.vocabularies {
+		for k, x := range vocabulary.validators {
+			if _, found := v.validators[k]; !found {
+				v.validators[k] = x
+			}
+		}
+	}
+
+	return v, true
 }
 
-func (e *Env) RegisterFormat(key string, v FormatValidator) {
-	if _, found := e.formats[key]; found {
-		panic("format is already registered")
+// DialectFormats returns the formats of the dialect identified by uri.
+func (e *Env) DialectFormats(uri string) (map[string]FormatValidator, bool) {
+	d, found := e.dialects[normalizeRef(uri)]
+	if !found {
+		return nil, false
+	}
+
+	formats := make(map[string]FormatValidator, len(d.formats))
+	for k, v := range d.formats {
+		formats[k] = v
+	}
+
+	return formats, true
+}
+
+// SetDefaultDialect selects the dialect used by the schemas which don't
+// declare a `$schema`.
+func (e *Env) SetDefaultDialect(uri string) error {
+	d, found := e.dialects[normalizeRef(uri)]
+	if !found {
+		return fmt.Errorf("unknown dialect: %s", uri)
 	}
-	e.formats[key] = v
+
+	e.dialect = d
+	return nil
+}
+
+// registerDialect makes d available to schemas which declare it as their
+// `$schema`.
+func (e *Env) registerDialect(d *dialect) {
+	if _, found := e.dialects[d.uri]; found {
+		panic("dialect is already registered")
+	}
+	e.dialects[d.uri] = d
 }
 
 func (e *Env) RegisterSchema(id string, data []byte) (*Schema, error) {
@@ -93,42 +267,163 @@ func (e *Env) RegisterSchema(id string, data []byte) (*Schema, error) {
 		return nil, err
 	}
 
+	return schema, e.registerSchema(schema)
+}
+
+// Schema returns the registered schema (or the subschema of a registered
+// schema) with the id id. Schema is not synchronized with RegisterSchema
+// (and with the remote schemas loaded by BuildSchema): the schemas must be
+// registered before Schema is called concurrently.
+func (e *Env) Schema(id string) (*Schema, bool) {
+	root, found := e.schemas[rootRef(id)]
+	if !found || root == nil {
+		return nil, false
+	}
+	schema, found := root.Subschemas[refFragment(id)]
+	return schema, found && schema != nil
+}
+
+func (e *Env) registerSchema(schema *Schema) error {
 	e.schemas[normalizeRef(schema.Id.String())] = schema
-	return schema, nil
+
+	if _, found := schema.Definition["$vocabulary"]; found {
+		return e.registerMetaSchemaDialect(schema)
+	}
+
+	return nil
+}
+
+// registerMetaSchemaDialect registers the dialect described by the
+// `$vocabulary` keyword of a meta schema. Unknown vocabularies are ignored
+// unless they are required.
+func (e *Env) registerMetaSchemaDialect(schema *Schema) error {
+	uri := normalizeRef(schema.Id.String())
+	if _, found := e.dialects[uri]; found {
+		return nil
+	}
+
+	x, ok := schema.Definition["$vocabulary"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid '$vocabulary' definition: %#v", schema.Definition["$vocabulary"])
+	}
+
+	keys := make([]string, 0, len(x))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var vocabularies []*Vocabulary
+	for _, k := range keys {
+		required, ok := x[k].(bool)
+		if !ok {
+			return fmt.Errorf("invalid '$vocabulary' definition: %#v", x)
+		}
+
+		vocabulary, found := e.vocabularies[k]
+		if !found {
+			if required {
+				return fmt.Errorf("unknown required vocabulary: %s", k)
+			}
+			continue
+		}
+
+		vocabularies = append(vocabularies, vocabulary)
+	}
+
+	base := e.dialectOf(schema.Definition)
+	d := newDialect(uri, base.idKeyword, vocabularies...)
+	for k, v := range base.formats {
+		d.formats[k] = v
+	}
+
+	e.registerDialect(d)
+	return nil
 }
 
+// dialectOf returns the dialect declared by the `$schema` keyword of obj or
+// the default dialect of the Env.
+func (e *Env) dialectOf(obj map[string]interface{}) *dialect {
+	if v, ok := obj["$schema"].(string); ok {
+		if d, found := e.dialects[normalizeRef(v)]; found {
+			return d
+		}
+	}
+	return e.dialect
+}
+
+// BuildSchema builds the schema document data. id is the URI of documents
+// without an id; it must match the id of the other documents. Boolean
+// documents (`true` and `false`) are accepted by all the dialects.
 func (e *Env) BuildSchema(id string, data []byte) (*Schema, error) {
+	return e.buildSchema(id, data, true)
+}
+
+// buildSchema builds the schema document data which is identified by id
+// unless it has an id. When strict is set that id must match id.
+func (e *Env) buildSchema(id string, data []byte, strict bool) (*Schema, error) {
 	var (
-		obj         map[string]interface{}
+		def         interface{}
 		superschema string
 	)
 
 	dec := json.NewDecoder(bytes.NewReader(data))
 	dec.UseNumber()
-	err := dec.Decode(&obj)
+	err := dec.Decode(&def)
 	if err != nil {
 		return nil, err
 	}
 
+	// obj is nil for boolean schemas
+	obj, _ := def.(map[string]interface{})
+
+	// unknown meta schemas are loaded so the dialects they declare with
+	// `$vocabulary` are known (see registerMetaSchemaDialect)
+	if v, ok := obj["$schema"].(string); ok && e.Transport != nil {
+		_, known := e.dialects[normalizeRef(v)]
+		_, loaded := e.schemas[rootRef(v)]
+		if !known && !loaded {
+			_, err := e.loadRemoteSchema(refURL(v))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	dialect := e.dialectOf(obj)
+
 	if v, ok := obj["$schema"].(string); ok {
 		superschema = normalizeRef(v)
 	}
 
 	if superschema == "" {
-		superschema = "http://json-schema.org/draft-04/schema#"
+		superschema = dialect.uri
 	}
 
-	if r, found := e.schemas[rootRef(superschema)]; found {
+	// boolean schemas are valid in all the dialects (even in the dialects
+	// whose meta schemas predate them)
+	_, boolean := def.(bool)
+
+	if r, found := e.schemas[rootRef(superschema)]; found && !boolean {
 		if s, found := r.Subschemas[refFragment(superschema)]; found {
-			err := s.Validate(obj)
+			err := s.Validate(def)
 			if err != nil {
 				return nil, err
 			}
 		}
 	}
 
-	builder := newBuilder(e)
-	schema, err := builder.Build(id, obj)
+	builder := newBuilder(e, dialect)
+
+	// documents without an id are identified by id
+	if id != "" {
+		builder.base, err = url.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schema, err := builder.Build("", def)
 	if err != nil {
 		return nil, err
 	}
@@ -138,13 +433,16 @@ func (e *Env) BuildSchema(id string, data []byte) (*Schema, error) {
 		return nil, err
 	}
 
-	if id != "" && normalizeRef(schema.Id.String()) != id {
+	if strict && id != "" && normalizeRef(schema.Id.String()) != normalizeRef(id) {
 		return nil, fmt.Errorf("schema id dit not match url (%q != %q)", id, schema.Id)
 	}
 
 	return schema, nil
 }
 
+// loadRemoteSchema loads and registers the schema document at url. Like in
+// BuildSchema, documents without an id are identified by url (so they are
+// loaded only once); the id of the other documents may differ from url.
 func (e *Env) loadRemoteSchema(url string) (*Schema, error) {
 	if e.Transport == nil {
 		return nil, fmt.Errorf("remote schema loading is not enabled (missing transport)")
@@ -155,5 +453,10 @@ func (e *Env) loadRemoteSchema(url string) (*Schema, error) {
 		return nil, err
 	}
 
-	return e.RegisterSchema("", data)
+	schema, err := e.buildSchema(url, data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, e.registerSchema(schema)
 }
@@ -0,0 +1,415 @@
+package jsonschema
+
+func init() {
+	core := NewVocabulary("https://json-schema.org/draft/2019-09/vocab/core")
+	core.RegisterKeyword(&annotationValidator{}, 10, "$id", "$schema", "$anchor", "$recursiveAnchor", "$vocabulary", "$comment")
+	core.RegisterKeyword(&defsValidator{}, 11, "$defs")
+	core.RegisterKeyword(&refValidator{}, 12, "$ref")
+	core.RegisterKeyword(&recursiveRefValidator{}, 13, "$recursiveRef")
+
+	applicator := NewVocabulary("https://json-schema.org/draft/2019-09/vocab/applicator")
+	applicator.RegisterKeyword(&anyOfValidator{}, 102, "anyOf")
+	applicator.RegisterKeyword(&allOfValidator{}, 103, "allOf")
+	applicator.RegisterKeyword(&oneOfValidator{}, 104, "oneOf")
+	applicator.RegisterKeyword(&notValidator{}, 105, "not")
+	applicator.RegisterKeyword(&ifValidator{}, 109, "if", "then", "else")
+	applicator.RegisterKeyword(&itemsValidator{}, 400, "items", "additionalItems")
+	applicator.RegisterKeyword(&boundedContainsValidator{}, 404, "contains", "minContains", "maxContains")
+	applicator.RegisterKeyword(&propertiesValidator{}, 503, "properties", "patternProperties", "additionalProperties")
+	applicator.RegisterKeyword(&propertyNamesValidator{}, 505, "propertyNames")
+	applicator.RegisterKeyword(&dependentSchemasValidator{}, 506, "dependentSchemas")
+	applicator.RegisterKeyword(&unevaluatedItemsValidator{}, 900, "unevaluatedItems")
+	applicator.RegisterKeyword(&unevaluatedPropertiesValidator{}, 901, "unevaluatedProperties")
+
+	validation := NewVocabulary("https://json-schema.org/draft/2019-09/vocab/validation")
+	validation.RegisterKeyword(&integralTypeValidator{}, 100, "type")
+	validation.RegisterKeyword(&enumValidator{}, 101, "enum")
+	validation.RegisterKeyword(&constValidator{}, 108, "const")
+	validation.RegisterKeyword(&multipleOfValidator{}, 200, "multipleOf")
+	validation.RegisterKeyword(&maximumValidator{}, 201, "maximum")
+	validation.RegisterKeyword(&minimumValidator{}, 202, "minimum")
+	validation.RegisterKeyword(&exclusiveMaximumValidator{}, 203, "exclusiveMaximum")
+	validation.RegisterKeyword(&exclusiveMinimumValidator{}, 204, "exclusiveMinimum")
+	validation.RegisterKeyword(&maxLengthValidator{}, 300, "maxLength")
+	validation.RegisterKeyword(&minLengthValidator{}, 301, "minLength")
+	validation.RegisterKeyword(&patternValidator{}, 302, "pattern")
+	validation.RegisterKeyword(&maxItemsValidator{}, 401, "maxItems")
+	validation.RegisterKeyword(&minItemsValidator{}, 402, "minItems")
+	validation.RegisterKeyword(&uniqueItemsValidator{}, 403, "uniqueItems")
+	validation.RegisterKeyword(&maxPropertiesValidator{}, 500, "maxProperties")
+	validation.RegisterKeyword(&minPropertiesValidator{}, 501, "minProperties")
+	validation.RegisterKeyword(&requiredValidator{}, 502, "required")
+	validation.RegisterKeyword(&dependentRequiredValidator{}, 507, "dependentRequired")
+
+	metaData := NewVocabulary("https://json-schema.org/draft/2019-09/vocab/meta-data")
+	metaData.RegisterKeyword(&annotationValidator{}, 110, "title", "description", "default", "deprecated", "readOnly", "writeOnly", "examples")
+
+	format := NewVocabulary("https://json-schema.org/draft/2019-09/vocab/format")
+	format.RegisterKeyword(&annotatingFormatValidator{}, 107, "format")
+
+	content := NewVocabulary("https://json-schema.org/draft/2019-09/vocab/content")
+	content.RegisterKeyword(&annotationValidator{}, 111, "contentEncoding", "contentMediaType", "contentSchema")
+
+	vocabularies := []*Vocabulary{core, applicator, validation, metaData, format, content}
+	for _, v := range vocabularies {
+		RootEnv.RegisterVocabulary(v)
+	}
+
+	d := newDialect("https://json-schema.org/draft/2019-09/schema", "$id", vocabularies...)
+
+	d.registerFormat("date", &dateFormat{})
+	d.registerFormat("date-time", &datetimeFormat{})
+	d.registerFormat("email", &emailFormat{})
+	d.registerFormat("hostname", &hostnameFormat{})
+	d.registerFormat("ipv4", &ipv4Format{})
+	d.registerFormat("ipv6", &ipv6Format{})
+	d.registerFormat("json-pointer", &jsonPointerFormat{})
+	d.registerFormat("regex", &regexFormat{})
+	d.registerFormat("relative-json-pointer", &relativeJSONPointerFormat{})
+	d.registerFormat("time", &timeFormat{})
+	d.registerFormat("uri", &uriFormat{})
+	d.registerFormat("uri-reference", &uriReferenceFormat{})
+	d.registerFormat("uri-template", &uriTemplateFormat{})
+
+	RootEnv.registerDialect(d)
+
+	// Set the draft 2019-09 meta schemas
+	for _, data := range draft2019_09 {
+		_, err := RootEnv.RegisterSchema("", data)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	schema := RootEnv.schemas["https://json-schema.org/draft/2019-09/schema#"]
+	for _, data := range draft2019_09 {
+		err := schema.ValidateData(data)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// draft2019_09 are the draft 2019-09 meta schemas. The vocabulary meta
+// schemas must be registered before the dialect meta schema that refers to
+// them.
+var draft2019_09 = [][]byte{
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://json-schema.org/draft/2019-09/meta/core",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2019-09/vocab/core": true
+		},
+		"$recursiveAnchor": true,
+		"title": "Core vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"$id": {
+				"type": "string",
+				"format": "uri-reference",
+				"$comment": "Non-empty fragments not allowed.",
+				"pattern": "^[^#]*#?$"
+			},
+			"$schema": {
+				"type": "string",
+				"format": "uri"
+			},
+			"$anchor": {
+				"type": "string",
+				"pattern": "^[A-Za-z][-A-Za-z0-9.:_]*$"
+			},
+			"$ref": {
+				"type": "string",
+				"format": "uri-reference"
+			},
+			"$recursiveRef": {
+				"type": "string",
+				"format": "uri-reference"
+			},
+			"$recursiveAnchor": {
+				"type": "boolean",
+				"default": false
+			},
+			"$vocabulary": {
+				"type": "object",
+				"propertyNames": {
+					"type": "string",
+					"format": "uri"
+				},
+				"additionalProperties": {
+					"type": "boolean"
+				}
+			},
+			"$comment": {
+				"type": "string"
+			},
+			"$defs": {
+				"type": "object",
+				"additionalProperties": { "$recursiveRef": "#" },
+				"default": {}
+			}
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://json-schema.org/draft/2019-09/meta/applicator",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2019-09/vocab/applicator": true
+		},
+		"$recursiveAnchor": true,
+		"title": "Applicator vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"additionalItems": { "$recursiveRef": "#" },
+			"unevaluatedItems": { "$recursiveRef": "#" },
+			"items": {
+				"anyOf": [
+					{ "$recursiveRef": "#" },
+					{ "$ref": "#/$defs/schemaArray" }
+				]
+			},
+			"contains": { "$recursiveRef": "#" },
+			"additionalProperties": { "$recursiveRef": "#" },
+			"unevaluatedProperties": { "$recursiveRef": "#" },
+			"properties": {
+				"type": "object",
+				"additionalProperties": { "$recursiveRef": "#" },
+				"default": {}
+			},
+			"patternProperties": {
+				"type": "object",
+				"additionalProperties": { "$recursiveRef": "#" },
+				"propertyNames": { "format": "regex" },
+				"default": {}
+			},
+			"dependentSchemas": {
+				"type": "object",
+				"additionalProperties": {
+					"$recursiveRef": "#"
+				}
+			},
+			"propertyNames": { "$recursiveRef": "#" },
+			"if": { "$recursiveRef": "#" },
+			"then": { "$recursiveRef": "#" },
+			"else": { "$recursiveRef": "#" },
+			"allOf": { "$ref": "#/$defs/schemaArray" },
+			"anyOf": { "$ref": "#/$defs/schemaArray" },
+			"oneOf": { "$ref": "#/$defs/schemaArray" },
+			"not": { "$recursiveRef": "#" }
+		},
+		"$defs": {
+			"schemaArray": {
+				"type": "array",
+				"minItems": 1,
+				"items": { "$recursiveRef": "#" }
+			}
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://json-schema.org/draft/2019-09/meta/validation",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2019-09/vocab/validation": true
+		},
+		"$recursiveAnchor": true,
+		"title": "Validation vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"type": {
+				"anyOf": [
+					{ "$ref": "#/$defs/simpleTypes" },
+					{
+						"type": "array",
+						"items": { "$ref": "#/$defs/simpleTypes" },
+						"minItems": 1,
+						"uniqueItems": true
+					}
+				]
+			},
+			"const": true,
+			"enum": {
+				"type": "array",
+				"items": true
+			},
+			"multipleOf": {
+				"type": "number",
+				"exclusiveMinimum": 0
+			},
+			"maximum": {
+				"type": "number"
+			},
+			"exclusiveMaximum": {
+				"type": "number"
+			},
+			"minimum": {
+				"type": "number"
+			},
+			"exclusiveMinimum": {
+				"type": "number"
+			},
+			"maxLength": { "$ref": "#/$defs/nonNegativeInteger" },
+			"minLength": { "$ref": "#/$defs/nonNegativeIntegerDefault0" },
+			"pattern": {
+				"type": "string",
+				"format": "regex"
+			},
+			"maxItems": { "$ref": "#/$defs/nonNegativeInteger" },
+			"minItems": { "$ref": "#/$defs/nonNegativeIntegerDefault0" },
+			"uniqueItems": {
+				"type": "boolean",
+				"default": false
+			},
+			"maxContains": { "$ref": "#/$defs/nonNegativeInteger" },
+			"minContains": {
+				"$ref": "#/$defs/nonNegativeInteger",
+				"default": 1
+			},
+			"maxProperties": { "$ref": "#/$defs/nonNegativeInteger" },
+			"minProperties": { "$ref": "#/$defs/nonNegativeIntegerDefault0" },
+			"required": { "$ref": "#/$defs/stringArray" },
+			"dependentRequired": {
+				"type": "object",
+				"additionalProperties": {
+					"$ref": "#/$defs/stringArray"
+				}
+			}
+		},
+		"$defs": {
+			"nonNegativeInteger": {
+				"type": "integer",
+				"minimum": 0
+			},
+			"nonNegativeIntegerDefault0": {
+				"$ref": "#/$defs/nonNegativeInteger",
+				"default": 0
+			},
+			"simpleTypes": {
+				"enum": [ "array", "boolean", "integer", "null", "number", "object", "string" ]
+			},
+			"stringArray": {
+				"type": "array",
+				"items": { "type": "string" },
+				"uniqueItems": true,
+				"default": []
+			}
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://json-schema.org/draft/2019-09/meta/meta-data",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2019-09/vocab/meta-data": true
+		},
+		"$recursiveAnchor": true,
+		"title": "Meta-data vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"title": {
+				"type": "string"
+			},
+			"description": {
+				"type": "string"
+			},
+			"default": true,
+			"deprecated": {
+				"type": "boolean",
+				"default": false
+			},
+			"readOnly": {
+				"type": "boolean",
+				"default": false
+			},
+			"writeOnly": {
+				"type": "boolean",
+				"default": false
+			},
+			"examples": {
+				"type": "array",
+				"items": true
+			}
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://json-schema.org/draft/2019-09/meta/format",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2019-09/vocab/format": true
+		},
+		"$recursiveAnchor": true,
+		"title": "Format vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"format": { "type": "string" }
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://json-schema.org/draft/2019-09/meta/content",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2019-09/vocab/content": true
+		},
+		"$recursiveAnchor": true,
+		"title": "Content vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"contentEncoding": { "type": "string" },
+			"contentMediaType": { "type": "string" },
+			"contentSchema": { "$recursiveRef": "#" }
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://json-schema.org/draft/2019-09/schema",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2019-09/vocab/core": true,
+			"https://json-schema.org/draft/2019-09/vocab/applicator": true,
+			"https://json-schema.org/draft/2019-09/vocab/validation": true,
+			"https://json-schema.org/draft/2019-09/vocab/meta-data": true,
+			"https://json-schema.org/draft/2019-09/vocab/format": false,
+			"https://json-schema.org/draft/2019-09/vocab/content": true
+		},
+		"$recursiveAnchor": true,
+		"title": "Core and Validation specifications meta-schema",
+		"allOf": [
+			{ "$ref": "meta/core" },
+			{ "$ref": "meta/applicator" },
+			{ "$ref": "meta/validation" },
+			{ "$ref": "meta/meta-data" },
+			{ "$ref": "meta/format" },
+			{ "$ref": "meta/content" }
+		],
+		"type": ["object", "boolean"],
+		"properties": {
+			"definitions": {
+				"$comment": "While no longer an official keyword as it is replaced by $defs, this keyword is retained in the meta-schema to prevent incompatible extensions as it remains in common use.",
+				"type": "object",
+				"additionalProperties": { "$recursiveRef": "#" },
+				"default": {}
+			},
+			"dependencies": {
+				"$comment": "\"dependencies\" is no longer a keyword, but schema authors should avoid redefining it to facilitate a smooth transition to \"dependentSchemas\" and \"dependentRequired\"",
+				"type": "object",
+				"additionalProperties": {
+					"anyOf": [
+						{ "$recursiveRef": "#" },
+						{ "$ref": "meta/validation#/$defs/stringArray" }
+					]
+				}
+			}
+		}
+	}
+	`),
+}
@@ -0,0 +1,456 @@
+package jsonschema
+
+func init() {
+	core := NewVocabulary("https://json-schema.org/draft/2020-12/vocab/core")
+	core.RegisterKeyword(&annotationValidator{}, 10, "$id", "$schema", "$anchor", "$dynamicAnchor", "$vocabulary", "$comment")
+	core.RegisterKeyword(&defsValidator{}, 11, "$defs")
+	core.RegisterKeyword(&refValidator{}, 12, "$ref")
+	core.RegisterKeyword(&dynamicRefValidator{}, 13, "$dynamicRef")
+
+	applicator := NewVocabulary("https://json-schema.org/draft/2020-12/vocab/applicator")
+	applicator.RegisterKeyword(&anyOfValidator{}, 102, "anyOf")
+	applicator.RegisterKeyword(&allOfValidator{}, 103, "allOf")
+	applicator.RegisterKeyword(&oneOfValidator{}, 104, "oneOf")
+	applicator.RegisterKeyword(&notValidator{}, 105, "not")
+	applicator.RegisterKeyword(&ifValidator{}, 109, "if", "then", "else")
+	applicator.RegisterKeyword(&prefixItemsValidator{}, 400, "prefixItems", "items")
+	applicator.RegisterKeyword(&evaluatingContainsValidator{}, 404, "contains", "minContains", "maxContains")
+	applicator.RegisterKeyword(&propertiesValidator{}, 503, "properties", "patternProperties", "additionalProperties")
+	applicator.RegisterKeyword(&propertyNamesValidator{}, 505, "propertyNames")
+	applicator.RegisterKeyword(&dependentSchemasValidator{}, 506, "dependentSchemas")
+
+	unevaluated := NewVocabulary("https://json-schema.org/draft/2020-12/vocab/unevaluated")
+	unevaluated.RegisterKeyword(&unevaluatedItemsValidator{}, 900, "unevaluatedItems")
+	unevaluated.RegisterKeyword(&unevaluatedPropertiesValidator{}, 901, "unevaluatedProperties")
+
+	validation := NewVocabulary("https://json-schema.org/draft/2020-12/vocab/validation")
+	validation.RegisterKeyword(&integralTypeValidator{}, 100, "type")
+	validation.RegisterKeyword(&enumValidator{}, 101, "enum")
+	validation.RegisterKeyword(&constValidator{}, 108, "const")
+	validation.RegisterKeyword(&multipleOfValidator{}, 200, "multipleOf")
+	validation.RegisterKeyword(&maximumValidator{}, 201, "maximum")
+	validation.RegisterKeyword(&minimumValidator{}, 202, "minimum")
+	validation.RegisterKeyword(&exclusiveMaximumValidator{}, 203, "exclusiveMaximum")
+	validation.RegisterKeyword(&exclusiveMinimumValidator{}, 204, "exclusiveMinimum")
+	validation.RegisterKeyword(&maxLengthValidator{}, 300, "maxLength")
+	validation.RegisterKeyword(&minLengthValidator{}, 301, "minLength")
+	validation.RegisterKeyword(&patternValidator{}, 302, "pattern")
+	validation.RegisterKeyword(&maxItemsValidator{}, 401, "maxItems")
+	validation.RegisterKeyword(&minItemsValidator{}, 402, "minItems")
+	validation.RegisterKeyword(&uniqueItemsValidator{}, 403, "uniqueItems")
+	validation.RegisterKeyword(&maxPropertiesValidator{}, 500, "maxProperties")
+	validation.RegisterKeyword(&minPropertiesValidator{}, 501, "minProperties")
+	validation.RegisterKeyword(&requiredValidator{}, 502, "required")
+	validation.RegisterKeyword(&dependentRequiredValidator{}, 507, "dependentRequired")
+
+	metaData := NewVocabulary("https://json-schema.org/draft/2020-12/vocab/meta-data")
+	metaData.RegisterKeyword(&annotationValidator{}, 110, "title", "description", "default", "deprecated", "readOnly", "writeOnly", "examples")
+
+	format := NewVocabulary("https://json-schema.org/draft/2020-12/vocab/format-annotation")
+	format.RegisterKeyword(&annotatingFormatValidator{}, 107, "format")
+
+	// formatAssertion is not a vocabulary of the dialect; meta schemas opt
+	// in to it with their `$vocabulary`.
+	formatAssertion := NewVocabulary("https://json-schema.org/draft/2020-12/vocab/format-assertion")
+	formatAssertion.RegisterKeyword(&formatValidator{}, 107, "format")
+
+	content := NewVocabulary("https://json-schema.org/draft/2020-12/vocab/content")
+	content.RegisterKeyword(&annotationValidator{}, 111, "contentEncoding", "contentMediaType", "contentSchema")
+
+	vocabularies := []*Vocabulary{core, applicator, unevaluated, validation, metaData, format, content}
+	for _, v := range vocabularies {
+		RootEnv.RegisterVocabulary(v)
+	}
+	RootEnv.RegisterVocabulary(formatAssertion)
+
+	d := newDialect("https://json-schema.org/draft/2020-12/schema", "$id", vocabularies...)
+
+	d.registerFormat("date", &dateFormat{})
+	d.registerFormat("date-time", &datetimeFormat{})
+	d.registerFormat("email", &emailFormat{})
+	d.registerFormat("hostname", &hostnameFormat{})
+	d.registerFormat("ipv4", &ipv4Format{})
+	d.registerFormat("ipv6", &ipv6Format{})
+	d.registerFormat("json-pointer", &jsonPointerFormat{})
+	d.registerFormat("regex", &regexFormat{})
+	d.registerFormat("relative-json-pointer", &relativeJSONPointerFormat{})
+	d.registerFormat("time", &timeFormat{})
+	d.registerFormat("uri", &uriFormat{})
+	d.registerFormat("uri-reference", &uriReferenceFormat{})
+	d.registerFormat("uri-template", &uriTemplateFormat{})
+
+	RootEnv.registerDialect(d)
+
+	// Set the draft 2020-12 meta schemas
+	for _, data := range draft2020_12 {
+		_, err := RootEnv.RegisterSchema("", data)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	schema := RootEnv.schemas["https://json-schema.org/draft/2020-12/schema#"]
+	for _, data := range draft2020_12 {
+		err := schema.ValidateData(data)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// draft2020_12 are the draft 2020-12 meta schemas. The vocabulary meta
+// schemas must be registered before the dialect meta schema that refers to
+// them.
+var draft2020_12 = [][]byte{
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/meta/core",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/core": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Core vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"$id": {
+				"$ref": "#/$defs/uriReferenceString",
+				"$comment": "Non-empty fragments not allowed.",
+				"pattern": "^[^#]*#?$"
+			},
+			"$schema": { "$ref": "#/$defs/uriString" },
+			"$ref": { "$ref": "#/$defs/uriReferenceString" },
+			"$anchor": { "$ref": "#/$defs/anchorString" },
+			"$dynamicRef": { "$ref": "#/$defs/uriReferenceString" },
+			"$dynamicAnchor": { "$ref": "#/$defs/anchorString" },
+			"$vocabulary": {
+				"type": "object",
+				"propertyNames": { "$ref": "#/$defs/uriString" },
+				"additionalProperties": {
+					"type": "boolean"
+				}
+			},
+			"$comment": {
+				"type": "string"
+			},
+			"$defs": {
+				"type": "object",
+				"additionalProperties": { "$dynamicRef": "#meta" }
+			}
+		},
+		"$defs": {
+			"anchorString": {
+				"type": "string",
+				"pattern": "^[A-Za-z_][-A-Za-z0-9._]*$"
+			},
+			"uriString": {
+				"type": "string",
+				"format": "uri"
+			},
+			"uriReferenceString": {
+				"type": "string",
+				"format": "uri-reference"
+			}
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/meta/applicator",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/applicator": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Applicator vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"prefixItems": { "$ref": "#/$defs/schemaArray" },
+			"items": { "$dynamicRef": "#meta" },
+			"contains": { "$dynamicRef": "#meta" },
+			"additionalProperties": { "$dynamicRef": "#meta" },
+			"properties": {
+				"type": "object",
+				"additionalProperties": { "$dynamicRef": "#meta" },
+				"default": {}
+			},
+			"patternProperties": {
+				"type": "object",
+				"additionalProperties": { "$dynamicRef": "#meta" },
+				"propertyNames": { "format": "regex" },
+				"default": {}
+			},
+			"dependentSchemas": {
+				"type": "object",
+				"additionalProperties": { "$dynamicRef": "#meta" },
+				"default": {}
+			},
+			"propertyNames": { "$dynamicRef": "#meta" },
+			"if": { "$dynamicRef": "#meta" },
+			"then": { "$dynamicRef": "#meta" },
+			"else": { "$dynamicRef": "#meta" },
+			"allOf": { "$ref": "#/$defs/schemaArray" },
+			"anyOf": { "$ref": "#/$defs/schemaArray" },
+			"oneOf": { "$ref": "#/$defs/schemaArray" },
+			"not": { "$dynamicRef": "#meta" }
+		},
+		"$defs": {
+			"schemaArray": {
+				"type": "array",
+				"minItems": 1,
+				"items": { "$dynamicRef": "#meta" }
+			}
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/meta/unevaluated",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/unevaluated": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Unevaluated applicator vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"unevaluatedItems": { "$dynamicRef": "#meta" },
+			"unevaluatedProperties": { "$dynamicRef": "#meta" }
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/meta/validation",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/validation": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Validation vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"type": {
+				"anyOf": [
+					{ "$ref": "#/$defs/simpleTypes" },
+					{
+						"type": "array",
+						"items": { "$ref": "#/$defs/simpleTypes" },
+						"minItems": 1,
+						"uniqueItems": true
+					}
+				]
+			},
+			"const": true,
+			"enum": {
+				"type": "array",
+				"items": true
+			},
+			"multipleOf": {
+				"type": "number",
+				"exclusiveMinimum": 0
+			},
+			"maximum": {
+				"type": "number"
+			},
+			"exclusiveMaximum": {
+				"type": "number"
+			},
+			"minimum": {
+				"type": "number"
+			},
+			"exclusiveMinimum": {
+				"type": "number"
+			},
+			"maxLength": { "$ref": "#/$defs/nonNegativeInteger" },
+			"minLength": { "$ref": "#/$defs/nonNegativeIntegerDefault0" },
+			"pattern": {
+				"type": "string",
+				"format": "regex"
+			},
+			"maxItems": { "$ref": "#/$defs/nonNegativeInteger" },
+			"minItems": { "$ref": "#/$defs/nonNegativeIntegerDefault0" },
+			"uniqueItems": {
+				"type": "boolean",
+				"default": false
+			},
+			"maxContains": { "$ref": "#/$defs/nonNegativeInteger" },
+			"minContains": {
+				"$ref": "#/$defs/nonNegativeInteger",
+				"default": 1
+			},
+			"maxProperties": { "$ref": "#/$defs/nonNegativeInteger" },
+			"minProperties": { "$ref": "#/$defs/nonNegativeIntegerDefault0" },
+			"required": { "$ref": "#/$defs/stringArray" },
+			"dependentRequired": {
+				"type": "object",
+				"additionalProperties": {
+					"$ref": "#/$defs/stringArray"
+				}
+			}
+		},
+		"$defs": {
+			"nonNegativeInteger": {
+				"type": "integer",
+				"minimum": 0
+			},
+			"nonNegativeIntegerDefault0": {
+				"$ref": "#/$defs/nonNegativeInteger",
+				"default": 0
+			},
+			"simpleTypes": {
+				"enum": [ "array", "boolean", "integer", "null", "number", "object", "string" ]
+			},
+			"stringArray": {
+				"type": "array",
+				"items": { "type": "string" },
+				"uniqueItems": true,
+				"default": []
+			}
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/meta/meta-data",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/meta-data": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Meta-data vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"title": {
+				"type": "string"
+			},
+			"description": {
+				"type": "string"
+			},
+			"default": true,
+			"deprecated": {
+				"type": "boolean",
+				"default": false
+			},
+			"readOnly": {
+				"type": "boolean",
+				"default": false
+			},
+			"writeOnly": {
+				"type": "boolean",
+				"default": false
+			},
+			"examples": {
+				"type": "array",
+				"items": true
+			}
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/meta/format-annotation",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/format-annotation": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Format vocabulary meta-schema for annotation results",
+		"type": ["object", "boolean"],
+		"properties": {
+			"format": { "type": "string" }
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/meta/format-assertion",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/format-assertion": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Format vocabulary meta-schema for assertion results",
+		"type": ["object", "boolean"],
+		"properties": {
+			"format": { "type": "string" }
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/meta/content",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/content": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Content vocabulary meta-schema",
+		"type": ["object", "boolean"],
+		"properties": {
+			"contentEncoding": { "type": "string" },
+			"contentMediaType": { "type": "string" },
+			"contentSchema": { "$dynamicRef": "#meta" }
+		}
+	}
+	`),
+	[]byte(`
+	{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://json-schema.org/draft/2020-12/schema",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/core": true,
+			"https://json-schema.org/draft/2020-12/vocab/applicator": true,
+			"https://json-schema.org/draft/2020-12/vocab/unevaluated": true,
+			"https://json-schema.org/draft/2020-12/vocab/validation": true,
+			"https://json-schema.org/draft/2020-12/vocab/meta-data": true,
+			"https://json-schema.org/draft/2020-12/vocab/format-annotation": true,
+			"https://json-schema.org/draft/2020-12/vocab/content": true
+		},
+		"$dynamicAnchor": "meta",
+		"title": "Core and Validation specifications meta-schema",
+		"allOf": [
+			{ "$ref": "meta/core" },
+			{ "$ref": "meta/applicator" },
+			{ "$ref": "meta/unevaluated" },
+			{ "$ref": "meta/validation" },
+			{ "$ref": "meta/meta-data" },
+			{ "$ref": "meta/format-annotation" },
+			{ "$ref": "meta/content" }
+		],
+		"type": ["object", "boolean"],
+		"$comment": "This meta-schema also defines keywords that have appeared in previous drafts in order to prevent incompatible extensions as they remain in common use.",
+		"properties": {
+			"definitions": {
+				"$comment": "\"definitions\" has been replaced by \"$defs\".",
+				"type": "object",
+				"additionalProperties": { "$dynamicRef": "#meta" },
+				"deprecated": true,
+				"default": {}
+			},
+			"dependencies": {
+				"$comment": "\"dependencies\" has been split and replaced by \"dependentSchemas\" and \"dependentRequired\" in order to serve their differing semantics.",
+				"type": "object",
+				"additionalProperties": {
+					"anyOf": [
+						{ "$dynamicRef": "#meta" },
+						{ "$ref": "meta/validation#/$defs/stringArray" }
+					]
+				},
+				"deprecated": true,
+				"default": {}
+			},
+			"$recursiveAnchor": {
+				"$comment": "\"$recursiveAnchor\" has been replaced by \"$dynamicAnchor\".",
+				"$ref": "meta/core#/$defs/anchorString",
+				"deprecated": true
+			},
+			"$recursiveRef": {
+				"$comment": "\"$recursiveRef\" has been replaced by \"$dynamicRef\".",
+				"$ref": "meta/core#/$defs/uriReferenceString",
+				"deprecated": true
+			}
+		}
+	}
+	`),
+}
@@ -0,0 +1,232 @@
+package jsonschema
+
+func init() {
+	d := newDialect("http://json-schema.org/draft-03/schema#", "id")
+
+	// any
+	d.registerKeyword(&unionTypeValidator{}, 100, "type")
+	d.registerKeyword(&enumValidator{}, 101, "enum")
+	d.registerKeyword(&disallowValidator{}, 102, "disallow")
+	d.registerKeyword(&extendsValidator{}, 103, "extends")
+	d.registerKeyword(&formatValidator{}, 107, "format")
+
+	// numbers
+	d.registerKeyword(&divisibleByValidator{}, 200, "divisibleBy")
+	d.registerKeyword(&maximumValidator{}, 201, "maximum", "exclusiveMaximum")
+	d.registerKeyword(&minimumValidator{}, 202, "minimum", "exclusiveMinimum")
+
+	// strings
+	d.registerKeyword(&maxLengthValidator{}, 300, "maxLength")
+	d.registerKeyword(&minLengthValidator{}, 301, "minLength")
+	d.registerKeyword(&patternValidator{}, 302, "pattern")
+
+	// arrays
+	d.registerKeyword(&itemsValidator{}, 400, "items", "additionalItems")
+	d.registerKeyword(&maxItemsValidator{}, 401, "maxItems")
+	d.registerKeyword(&minItemsValidator{}, 402, "minItems")
+	d.registerKeyword(&uniqueItemsValidator{}, 403, "uniqueItems")
+
+	// objects
+	d.registerKeyword(&requiredPropertiesValidator{}, 503, "properties", "patternProperties", "additionalProperties")
+	d.registerKeyword(&dependenciesValidator{}, 504, "dependencies")
+
+	d.registerFormat("color", &colorFormat{})
+	d.registerFormat("date", &dateFormat{})
+	d.registerFormat("date-time", &datetimeFormat{})
+	d.registerFormat("email", &emailFormat{})
+	d.registerFormat("host-name", &hostnameFormat{})
+	d.registerFormat("ip-address", &ipv4Format{})
+	d.registerFormat("ipv6", &ipv6Format{})
+	d.registerFormat("regex", &regexFormat{})
+	d.registerFormat("time", &localTimeFormat{})
+	d.registerFormat("uri", &uriFormat{})
+	d.registerFormat("utc-millisec", &utcMillisecFormat{})
+
+	RootEnv.registerDialect(d)
+
+	// Set the draft-03 meta schema
+	schema, err := RootEnv.RegisterSchema("", draft3)
+	if err != nil {
+		panic(err)
+	}
+
+	err = schema.ValidateData(draft3)
+	if err != nil {
+		panic(err)
+	}
+}
+
+var draft3 = []byte(`
+	{
+		"$schema": "http://json-schema.org/draft-03/schema#",
+		"id": "http://json-schema.org/draft-03/schema#",
+		"type": "object",
+
+		"properties": {
+			"type": {
+				"type": ["string", "array"],
+				"items": {
+					"type": ["string", {"$ref": "#"}]
+				},
+				"uniqueItems": true,
+				"default": "any"
+			},
+
+			"properties": {
+				"type": "object",
+				"additionalProperties": {"$ref": "#", "type": "object"},
+				"default": {}
+			},
+
+			"patternProperties": {
+				"type": "object",
+				"additionalProperties": {"$ref": "#"},
+				"default": {}
+			},
+
+			"additionalProperties": {
+				"type": [{"$ref": "#"}, "boolean"],
+				"default": {}
+			},
+
+			"items": {
+				"type": [{"$ref": "#"}, "array"],
+				"items": {"$ref": "#"},
+				"default": {}
+			},
+
+			"additionalItems": {
+				"type": [{"$ref": "#"}, "boolean"],
+				"default": {}
+			},
+
+			"required": {
+				"type": "boolean",
+				"default": false
+			},
+
+			"dependencies": {
+				"type": "object",
+				"additionalProperties": {
+					"type": ["string", "array", {"$ref": "#"}],
+					"items": {
+						"type": "string"
+					}
+				},
+				"default": {}
+			},
+
+			"minimum": {
+				"type": "number"
+			},
+
+			"maximum": {
+				"type": "number"
+			},
+
+			"exclusiveMinimum": {
+				"type": "boolean",
+				"default": false
+			},
+
+			"exclusiveMaximum": {
+				"type": "boolean",
+				"default": false
+			},
+
+			"minItems": {
+				"type": "integer",
+				"minimum": 0,
+				"default": 0
+			},
+
+			"maxItems": {
+				"type": "integer",
+				"minimum": 0
+			},
+
+			"uniqueItems": {
+				"type": "boolean",
+				"default": false
+			},
+
+			"pattern": {
+				"type": "string",
+				"format": "regex"
+			},
+
+			"minLength": {
+				"type": "integer",
+				"minimum": 0,
+				"default": 0
+			},
+
+			"maxLength": {
+				"type": "integer"
+			},
+
+			"enum": {
+				"type": "array",
+				"minItems": 1,
+				"uniqueItems": true
+			},
+
+			"default": {
+				"type": "any"
+			},
+
+			"title": {
+				"type": "string"
+			},
+
+			"description": {
+				"type": "string"
+			},
+
+			"format": {
+				"type": "string"
+			},
+
+			"divisibleBy": {
+				"type": "number",
+				"minimum": 0,
+				"exclusiveMinimum": true,
+				"default": 1
+			},
+
+			"disallow": {
+				"type": ["string", "array"],
+				"items": {
+					"type": ["string", {"$ref": "#"}]
+				},
+				"uniqueItems": true
+			},
+
+			"extends": {
+				"type": [{"$ref": "#"}, "array"],
+				"items": {"$ref": "#"},
+				"default": {}
+			},
+
+			"id": {
+				"type": "string"
+			},
+
+			"$ref": {
+				"type": "string"
+			},
+
+			"$schema": {
+				"type": "string",
+				"format": "uri"
+			}
+		},
+
+		"dependencies": {
+			"exclusiveMinimum": "minimum",
+			"exclusiveMaximum": "maximum"
+		},
+
+		"default": {}
+	}
+`)
@@ -45,6 +45,8 @@ func init() {
 	RootEnv.RegisterFormat("uri", &uriFormat{})
 	RootEnv.RegisterFormat("uri-reference", &uriReferenceFormat{})
 
+	RootEnv.registerDialect(RootEnv.dialect)
+
 	// Set the root Schema
 	schema, err := RootEnv.RegisterSchema("", draft4)
 	if err != nil {
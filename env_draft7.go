@@ -0,0 +1,237 @@
+package jsonschema
+
+func init() {
+	d := newDialect("http://json-schema.org/draft-07/schema#", "$id")
+
+	// any
+	d.registerKeyword(&integralTypeValidator{}, 100, "type")
+	d.registerKeyword(&enumValidator{}, 101, "enum")
+	d.registerKeyword(&anyOfValidator{}, 102, "anyOf")
+	d.registerKeyword(&allOfValidator{}, 103, "allOf")
+	d.registerKeyword(&oneOfValidator{}, 104, "oneOf")
+	d.registerKeyword(&notValidator{}, 105, "not")
+	d.registerKeyword(&definitionsValidator{}, 106, "definitions")
+	d.registerKeyword(&formatValidator{}, 107, "format")
+	d.registerKeyword(&constValidator{}, 108, "const")
+	d.registerKeyword(&ifValidator{}, 109, "if", "then", "else")
+	d.registerKeyword(&annotationValidator{}, 110, "$comment", "readOnly", "writeOnly", "contentMediaType", "contentEncoding")
+
+	// numbers
+	d.registerKeyword(&multipleOfValidator{}, 200, "multipleOf")
+	d.registerKeyword(&maximumValidator{}, 201, "maximum")
+	d.registerKeyword(&minimumValidator{}, 202, "minimum")
+	d.registerKeyword(&exclusiveMaximumValidator{}, 203, "exclusiveMaximum")
+	d.registerKeyword(&exclusiveMinimumValidator{}, 204, "exclusiveMinimum")
+
+	// strings
+	d.registerKeyword(&maxLengthValidator{}, 300, "maxLength")
+	d.registerKeyword(&minLengthValidator{}, 301, "minLength")
+	d.registerKeyword(&patternValidator{}, 302, "pattern")
+
+	// arrays
+	d.registerKeyword(&itemsValidator{}, 400, "items", "additionalItems")
+	d.registerKeyword(&maxItemsValidator{}, 401, "maxItems")
+	d.registerKeyword(&minItemsValidator{}, 402, "minItems")
+	d.registerKeyword(&uniqueItemsValidator{}, 403, "uniqueItems")
+	d.registerKeyword(&containsValidator{}, 404, "contains")
+
+	// objects
+	d.registerKeyword(&maxPropertiesValidator{}, 500, "maxProperties")
+	d.registerKeyword(&minPropertiesValidator{}, 501, "minProperties")
+	d.registerKeyword(&requiredValidator{}, 502, "required")
+	d.registerKeyword(&propertiesValidator{}, 503, "properties", "patternProperties", "additionalProperties")
+	d.registerKeyword(&dependenciesValidator{}, 504, "dependencies")
+	d.registerKeyword(&propertyNamesValidator{}, 505, "propertyNames")
+
+	d.registerFormat("date", &dateFormat{})
+	d.registerFormat("date-time", &datetimeFormat{})
+	d.registerFormat("email", &emailFormat{})
+	d.registerFormat("hostname", &hostnameFormat{})
+	d.registerFormat("ipv4", &ipv4Format{})
+	d.registerFormat("ipv6", &ipv6Format{})
+	d.registerFormat("json-pointer", &jsonPointerFormat{})
+	d.registerFormat("regex", &regexFormat{})
+	d.registerFormat("relative-json-pointer", &relativeJSONPointerFormat{})
+	d.registerFormat("time", &timeFormat{})
+	d.registerFormat("uri", &uriFormat{})
+	d.registerFormat("uri-reference", &uriReferenceFormat{})
+	d.registerFormat("uri-template", &uriTemplateFormat{})
+
+	RootEnv.registerDialect(d)
+
+	// Set the draft-07 meta schema
+	schema, err := RootEnv.RegisterSchema("", draft7)
+	if err != nil {
+		panic(err)
+	}
+
+	err = schema.ValidateData(draft7)
+	if err != nil {
+		panic(err)
+	}
+}
+
+var draft7 = []byte(`
+	{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "http://json-schema.org/draft-07/schema#",
+		"title": "Core schema meta-schema",
+		"definitions": {
+			"schemaArray": {
+				"type": "array",
+				"minItems": 1,
+				"items": { "$ref": "#" }
+			},
+			"nonNegativeInteger": {
+				"type": "integer",
+				"minimum": 0
+			},
+			"nonNegativeIntegerDefault0": {
+				"allOf": [
+					{ "$ref": "#/definitions/nonNegativeInteger" },
+					{ "default": 0 }
+				]
+			},
+			"simpleTypes": {
+				"enum": [ "array", "boolean", "integer", "null", "number", "object", "string" ]
+			},
+			"stringArray": {
+				"type": "array",
+				"items": { "type": "string" },
+				"uniqueItems": true,
+				"default": []
+			}
+		},
+		"type": ["object", "boolean"],
+		"properties": {
+			"$id": {
+				"type": "string",
+				"format": "uri-reference"
+			},
+			"$schema": {
+				"type": "string",
+				"format": "uri"
+			},
+			"$ref": {
+				"type": "string",
+				"format": "uri-reference"
+			},
+			"$comment": {
+				"type": "string"
+			},
+			"title": {
+				"type": "string"
+			},
+			"description": {
+				"type": "string"
+			},
+			"default": true,
+			"readOnly": {
+				"type": "boolean",
+				"default": false
+			},
+			"writeOnly": {
+				"type": "boolean",
+				"default": false
+			},
+			"examples": {
+				"type": "array",
+				"items": true
+			},
+			"multipleOf": {
+				"type": "number",
+				"exclusiveMinimum": 0
+			},
+			"maximum": {
+				"type": "number"
+			},
+			"exclusiveMaximum": {
+				"type": "number"
+			},
+			"minimum": {
+				"type": "number"
+			},
+			"exclusiveMinimum": {
+				"type": "number"
+			},
+			"maxLength": { "$ref": "#/definitions/nonNegativeInteger" },
+			"minLength": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+			"pattern": {
+				"type": "string",
+				"format": "regex"
+			},
+			"additionalItems": { "$ref": "#" },
+			"items": {
+				"anyOf": [
+					{ "$ref": "#" },
+					{ "$ref": "#/definitions/schemaArray" }
+				],
+				"default": true
+			},
+			"maxItems": { "$ref": "#/definitions/nonNegativeInteger" },
+			"minItems": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+			"uniqueItems": {
+				"type": "boolean",
+				"default": false
+			},
+			"contains": { "$ref": "#" },
+			"maxProperties": { "$ref": "#/definitions/nonNegativeInteger" },
+			"minProperties": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+			"required": { "$ref": "#/definitions/stringArray" },
+			"additionalProperties": { "$ref": "#" },
+			"definitions": {
+				"type": "object",
+				"additionalProperties": { "$ref": "#" },
+				"default": {}
+			},
+			"properties": {
+				"type": "object",
+				"additionalProperties": { "$ref": "#" },
+				"default": {}
+			},
+			"patternProperties": {
+				"type": "object",
+				"additionalProperties": { "$ref": "#" },
+				"propertyNames": { "format": "regex" },
+				"default": {}
+			},
+			"dependencies": {
+				"type": "object",
+				"additionalProperties": {
+					"anyOf": [
+						{ "$ref": "#" },
+						{ "$ref": "#/definitions/stringArray" }
+					]
+				}
+			},
+			"propertyNames": { "$ref": "#" },
+			"const": true,
+			"enum": {
+				"type": "array",
+				"items": true
+			},
+			"type": {
+				"anyOf": [
+					{ "$ref": "#/definitions/simpleTypes" },
+					{
+						"type": "array",
+						"items": { "$ref": "#/definitions/simpleTypes" },
+						"minItems": 1,
+						"uniqueItems": true
+					}
+				]
+			},
+			"format": { "type": "string" },
+			"contentMediaType": { "type": "string" },
+			"contentEncoding": { "type": "string" },
+			"if": { "$ref": "#" },
+			"then": { "$ref": "#" },
+			"else": { "$ref": "#" },
+			"allOf": { "$ref": "#/definitions/schemaArray" },
+			"anyOf": { "$ref": "#/definitions/schemaArray" },
+			"oneOf": { "$ref": "#/definitions/schemaArray" },
+			"not": { "$ref": "#" }
+		},
+		"default": true
+	}
+`)
@@ -0,0 +1,211 @@
+package jsonschema
+
+// OpenAPI3Dialect identifies the OpenAPI 3.0 schema object dialect. The
+// OpenAPI 3.0 specification doesn't define a `$schema` URI for its schema
+// objects; this one is only meaningful to this package.
+const OpenAPI3Dialect = "https://spec.openapis.org/oas/3.0/schema-object#"
+
+// OpenAPI31Dialect identifies the base dialect of OpenAPI 3.1 schema
+// objects (draft 2020-12 with the OpenAPI vocabulary).
+const OpenAPI31Dialect = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// OpenAPIEnv builds schemas as OpenAPI 3.0 schema objects (like the entries
+// of `components.schemas`) unless they declare another `$schema`.
+var OpenAPIEnv *Env
+
+func init() {
+	d := newDialect(OpenAPI3Dialect, "")
+	d.extensionPrefix = "x-"
+
+	// any
+	d.registerKeyword(&nullableTypeValidator{}, 100, "type", "nullable")
+	d.registerKeyword(&enumValidator{}, 101, "enum")
+	d.registerKeyword(&anyOfValidator{}, 102, "anyOf")
+	d.registerKeyword(&allOfValidator{}, 103, "allOf")
+	d.registerKeyword(&oneOfValidator{}, 104, "oneOf")
+	d.registerKeyword(&notValidator{}, 105, "not")
+	d.registerKeyword(&openFormatValidator{}, 107, "format")
+	d.registerKeyword(&annotationValidator{}, 110, "title", "description", "default", "discriminator", "readOnly", "writeOnly", "example", "externalDocs", "deprecated", "xml")
+
+	// numbers
+	d.registerKeyword(&multipleOfValidator{}, 200, "multipleOf")
+	d.registerKeyword(&maximumValidator{}, 201, "maximum", "exclusiveMaximum")
+	d.registerKeyword(&minimumValidator{}, 202, "minimum", "exclusiveMinimum")
+
+	// strings
+	d.registerKeyword(&maxLengthValidator{}, 300, "maxLength")
+	d.registerKeyword(&minLengthValidator{}, 301, "minLength")
+	d.registerKeyword(&patternValidator{}, 302, "pattern")
+
+	// arrays
+	d.registerKeyword(&itemsValidator{}, 400, "items")
+	d.registerKeyword(&maxItemsValidator{}, 401, "maxItems")
+	d.registerKeyword(&minItemsValidator{}, 402, "minItems")
+	d.registerKeyword(&uniqueItemsValidator{}, 403, "uniqueItems")
+
+	// objects
+	d.registerKeyword(&maxPropertiesValidator{}, 500, "maxProperties")
+	d.registerKeyword(&minPropertiesValidator{}, 501, "minProperties")
+	d.registerKeyword(&requiredValidator{}, 502, "required")
+	d.registerKeyword(&propertiesValidator{}, 503, "properties", "additionalProperties")
+
+	d.registerFormat("byte", &byteFormat{})
+	d.registerFormat("date", &dateFormat{})
+	d.registerFormat("date-time", &datetimeFormat{})
+	d.registerFormat("double", &doubleFormat{})
+	d.registerFormat("email", &emailFormat{})
+	d.registerFormat("float", &floatFormat{})
+	d.registerFormat("hostname", &hostnameFormat{})
+	d.registerFormat("int32", &int32Format{})
+	d.registerFormat("int64", &int64Format{})
+	d.registerFormat("ipv4", &ipv4Format{})
+	d.registerFormat("ipv6", &ipv6Format{})
+	d.registerFormat("regex", &regexFormat{})
+	d.registerFormat("uri", &uriFormat{})
+	d.registerFormat("uri-reference", &uriReferenceFormat{})
+
+	RootEnv.registerDialect(d)
+
+	// Set the OpenAPI 3.0 schema object meta schema (itself a draft-04
+	// schema)
+	_, err := RootEnv.RegisterSchema("", openAPI3)
+	if err != nil {
+		panic(err)
+	}
+
+	// OpenAPI 3.1
+	oas := NewVocabulary("https://spec.openapis.org/oas/3.1/vocab/base")
+	oas.RegisterKeyword(&annotationValidator{}, 112, "discriminator", "xml", "externalDocs", "example")
+	RootEnv.RegisterVocabulary(oas)
+
+	base := RootEnv.dialects[normalizeRef("https://json-schema.org/draft/2020-12/schema")]
+	d = newDialect(OpenAPI31Dialect, "$id", append(base.vocabularies[:len(base.vocabularies):len(base.vocabularies)], oas)...)
+	d.extensionPrefix = "x-"
+	for k, v := range base.formats {
+		d.registerFormat(k, v)
+	}
+	RootEnv.registerDialect(d)
+
+	OpenAPIEnv = RootEnv.Clone()
+	err = OpenAPIEnv.SetDefaultDialect(OpenAPI3Dialect)
+	if err != nil {
+		panic(err)
+	}
+}
+
+var openAPI3 = []byte(`
+	{
+		"id": "https://spec.openapis.org/oas/3.0/schema-object#",
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"description": "OpenAPI 3.0 schema object meta-schema",
+		"definitions": {
+			"schemaArray": {
+				"type": "array",
+				"items": { "$ref": "#" }
+			},
+			"positiveInteger": {
+				"type": "integer",
+				"minimum": 0
+			},
+			"positiveIntegerDefault0": {
+				"allOf": [ { "$ref": "#/definitions/positiveInteger" }, { "default": 0 } ]
+			},
+			"discriminator": {
+				"type": "object",
+				"required": [ "propertyName" ],
+				"properties": {
+					"propertyName": { "type": "string" },
+					"mapping": {
+						"type": "object",
+						"additionalProperties": { "type": "string" }
+					}
+				}
+			},
+			"externalDocs": {
+				"type": "object",
+				"required": [ "url" ],
+				"properties": {
+					"description": { "type": "string" },
+					"url": { "type": "string", "format": "uri-reference" }
+				},
+				"patternProperties": { "^x-": {} },
+				"additionalProperties": false
+			},
+			"xml": {
+				"type": "object",
+				"properties": {
+					"name": { "type": "string" },
+					"namespace": { "type": "string", "format": "uri" },
+					"prefix": { "type": "string" },
+					"attribute": { "type": "boolean", "default": false },
+					"wrapped": { "type": "boolean", "default": false }
+				},
+				"patternProperties": { "^x-": {} },
+				"additionalProperties": false
+			}
+		},
+		"type": "object",
+		"properties": {
+			"$ref": { "type": "string", "format": "uri-reference" },
+			"title": { "type": "string" },
+			"multipleOf": {
+				"type": "number",
+				"minimum": 0,
+				"exclusiveMinimum": true
+			},
+			"maximum": { "type": "number" },
+			"exclusiveMaximum": { "type": "boolean", "default": false },
+			"minimum": { "type": "number" },
+			"exclusiveMinimum": { "type": "boolean", "default": false },
+			"maxLength": { "$ref": "#/definitions/positiveInteger" },
+			"minLength": { "$ref": "#/definitions/positiveIntegerDefault0" },
+			"pattern": { "type": "string", "format": "regex" },
+			"maxItems": { "$ref": "#/definitions/positiveInteger" },
+			"minItems": { "$ref": "#/definitions/positiveIntegerDefault0" },
+			"uniqueItems": { "type": "boolean", "default": false },
+			"maxProperties": { "$ref": "#/definitions/positiveInteger" },
+			"minProperties": { "$ref": "#/definitions/positiveIntegerDefault0" },
+			"required": {
+				"type": "array",
+				"items": { "type": "string" },
+				"minItems": 1,
+				"uniqueItems": true
+			},
+			"enum": {
+				"type": "array",
+				"minItems": 1
+			},
+			"type": {
+				"type": "string",
+				"enum": [ "array", "boolean", "integer", "number", "object", "string" ]
+			},
+			"not": { "$ref": "#" },
+			"allOf": { "$ref": "#/definitions/schemaArray" },
+			"oneOf": { "$ref": "#/definitions/schemaArray" },
+			"anyOf": { "$ref": "#/definitions/schemaArray" },
+			"items": { "$ref": "#" },
+			"properties": {
+				"type": "object",
+				"additionalProperties": { "$ref": "#" }
+			},
+			"additionalProperties": {
+				"anyOf": [ { "type": "boolean" }, { "$ref": "#" } ],
+				"default": {}
+			},
+			"description": { "type": "string" },
+			"format": { "type": "string" },
+			"default": {},
+			"nullable": { "type": "boolean", "default": false },
+			"discriminator": { "$ref": "#/definitions/discriminator" },
+			"readOnly": { "type": "boolean", "default": false },
+			"writeOnly": { "type": "boolean", "default": false },
+			"example": {},
+			"externalDocs": { "$ref": "#/definitions/externalDocs" },
+			"deprecated": { "type": "boolean", "default": false },
+			"xml": { "$ref": "#/definitions/xml" }
+		},
+		"patternProperties": { "^x-": {} },
+		"additionalProperties": false,
+		"default": {}
+	}
+`)
@@ -8,106 +8,313 @@ import (
 
 func init() {
 	var err Error
-	// err = &ErrInvalidDependency{}
-	// err = &ErrInvalidEnum{}
-	// err = &ErrInvalidFormat{}
-	// err = &ErrInvalidInstance{}
-	// err = &ErrInvalidItem{}
-	// err = &ErrInvalidPattern{}
-	// err = &ErrInvalidProperty{}
-	// err = &ErrInvalidType{}
+	err = &ErrDisallowedType{}
+	err = &ErrFalseSchema{}
+	err = &ErrInvalidDependency{}
+	err = &ErrInvalidEnum{}
+	err = &ErrInvalidFormat{}
+	err = &ErrInvalidInstance{}
+	err = &ErrInvalidItem{}
+	err = &ErrInvalidPattern{}
+	err = &ErrInvalidProperty{}
+	err = &ErrInvalidPropertyName{}
+	err = &ErrInvalidType{}
 	err = &ErrNotAllOf{}
-	// err = &ErrNotAnyOf{}
-	// err = &ErrNotMultipleOf{}
-	// err = &ErrNotNot{}
-	// err = &ErrNotOneOf{}
-	// err = &ErrNotUnique{}
-	// err = &ErrRequiredProperty{}
-	// err = &ErrTooLarge{}
-	// err = &ErrTooLong{}
-	// err = &ErrTooShort{}
-	// err = &ErrTooSmall{}
+	err = &ErrNotAnyOf{}
+	err = &ErrNotConst{}
+	err = &ErrNotContains{}
+	err = &ErrNotElse{}
+	err = &ErrNotMultipleOf{}
+	err = &ErrNotNot{}
+	err = &ErrNotOneOf{}
+	err = &ErrNotThen{}
+	err = &ErrNotUnique{}
+	err = &ErrRequiredProperty{}
+	err = &ErrTooLarge{}
+	err = &ErrTooLong{}
+	err = &ErrTooShort{}
+	err = &ErrTooSmall{}
 	_ = err
 }
 
+// Error is implemented by all the errors reported by the validators. The
+// errors which wrap the errors of subschemas also implement `Unwrap() error`
+// or `Unwrap() []error`.
 type Error interface {
 	Error() string
-	Value() interface{}
+
+	// Code identifies the kind of error.
+	Code() ErrorCode
+
+	// Keyword is the keyword which failed.
 	Keyword() string
+
+	// Expected is the limit or expected value of the keyword (like the
+	// `maximum` or the `enum` values). It is nil for keywords without one.
+	Expected() interface{}
+
+	// Value is the value the keyword was applied to.
+	Value() interface{}
+
+	// Schema is the schema which contains the keyword.
 	Schema() *Schema
+
+	InstanceLocation() string
+	KeywordLocation() string
+}
+
+// ErrorCode is a stable, machine readable identifier of the kind of an Error.
+type ErrorCode string
+
+const (
+	CodeDisallowedType      ErrorCode = "disallowed_type"
+	CodeFalseSchema         ErrorCode = "false_schema"
+	CodeInvalidDependency   ErrorCode = "invalid_dependency"
+	CodeInvalidEnum         ErrorCode = "invalid_enum"
+	CodeInvalidFormat       ErrorCode = "invalid_format"
+	CodeInvalidInstance     ErrorCode = "invalid_instance"
+	CodeInvalidItem         ErrorCode = "invalid_item"
+	CodeInvalidPattern      ErrorCode = "invalid_pattern"
+	CodeInvalidProperty     ErrorCode = "invalid_property"
+	CodeInvalidPropertyName ErrorCode = "invalid_property_name"
+	CodeInvalidType         ErrorCode = "invalid_type"
+	CodeNotAllOf            ErrorCode = "not_all_of"
+	CodeNotAnyOf            ErrorCode = "not_any_of"
+	CodeNotConst            ErrorCode = "not_const"
+	CodeNotContains         ErrorCode = "not_contains"
+	CodeNotElse             ErrorCode = "not_else"
+	CodeNotMultipleOf       ErrorCode = "not_multiple_of"
+	CodeNotNot              ErrorCode = "not_not"
+	CodeNotOneOf            ErrorCode = "not_one_of"
+	CodeNotThen             ErrorCode = "not_then"
+	CodeNotUnique           ErrorCode = "not_unique"
+	CodeRequiredProperty    ErrorCode = "required_property"
+	CodeTooLarge            ErrorCode = "too_large"
+	CodeTooLong             ErrorCode = "too_long"
+	CodeTooShort            ErrorCode = "too_short"
+	CodeTooSmall            ErrorCode = "too_small"
+)
+
+// errorBase is embedded in all errors. It is filled in when the error is
+// reported to the Context.
+type errorBase struct {
+	reported bool
+	keyword  string
+	value    interface{}
+	schema   *Schema
+
+	// location is the location of the frame which reported the error (the
+	// JSON pointers are built when they are asked for). The errors of the
+	// compiled validators have their pointers instead.
+	location         *frameLocation
+	instanceLocation string
+	keywordLocation  string
 }
 
-// ErrNotAllOf is returned when a `allOf` keyword failed.
-type ErrNotAllOf struct {
-	value      interface{}
-	schema     *Schema
-	subschemas []*Schema
-	errors     []error
+type reportable interface {
+	report(keyword string, value interface{}, schema *Schema, location *frameLocation, instanceLocation, keywordLocation string)
 }
 
-func (e *ErrNotAllOf) Schema() *Schema    { return e.schema }
-func (e *ErrNotAllOf) Value() interface{} { return e.value }
-func (e *ErrNotAllOf) Keyword() string    { return "allOf" }
+func (e *errorBase) Keyword() string    { return e.keyword }
+func (e *errorBase) Value() interface{} { return e.value }
+func (e *errorBase) Schema() *Schema    { return e.schema }
 
-func (e *ErrNotAllOf) Error() string {
-	var buf bytes.Buffer
+// InstanceLocation returns the JSON pointer to the invalid value in the
+// instance (like `/orders/3/lines/0/sku`).
+func (e *errorBase) InstanceLocation() string {
+	if e.location != nil {
+		return e.location.instanceLocation()
+	}
+	return e.instanceLocation
+}
 
-	fmt.Fprintf(&buf, "value must be all of:")
+// KeywordLocation returns the JSON pointer to the keyword which failed,
+// through the references which were followed (like
+// `/properties/orders/items/$ref/required`).
+func (e *errorBase) KeywordLocation() string {
+	if e.location != nil {
+		return joinKeywordLocation(e.location.keywordLocation(), e.keyword)
+	}
+	return e.keywordLocation
+}
 
-	for i, schema := range e.subschemas {
+// report fills in the error unless it was already reported elsewhere (like
+// the errors of a referenced schema).
+func (e *errorBase) report(keyword string, value interface{}, schema *Schema, location *frameLocation, instanceLocation, keywordLocation string) {
+	if e.reported {
+		return
+	}
+	e.reported = true
+	e.keyword = keyword
+	e.value = value
+	e.schema = schema
+	e.location = location
+	e.instanceLocation = instanceLocation
+	e.keywordLocation = keywordLocation
+}
+
+// nonNilErrors returns the errors of the subschemas which failed.
+func nonNilErrors(errs []error) []error {
+	var result []error
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+func formatSubschemaErrors(buf *bytes.Buffer, schemas []*Schema, errs []error) {
+	for i, schema := range schemas {
 		var (
-			err    = e.errors[i]
+			err    error
 			errstr = "<nil>"
 		)
 
+		if i < len(errs) {
+			err = errs[i]
+		}
 		if err != nil {
 			errstr = strings.Replace(err.Error(), "\n", "\n    ", -1)
 		}
 
-		fmt.Fprintf(&buf, "\n- schema: %v\n  error:\n    %v", schema.Id.String(), errstr)
+		fmt.Fprintf(buf, "\n- schema: %v\n  error:\n    %v", schema.Id.String(), errstr)
 	}
+}
+
+// ErrNotAllOf is returned when a `allOf` keyword failed. Errors has the
+// error of each of the Subschemas (nil for the valid ones).
+type ErrNotAllOf struct {
+	errorBase
+	Subschemas []*Schema
+	Errors     []error
+}
+
+func (e *ErrNotAllOf) Code() ErrorCode       { return CodeNotAllOf }
+func (e *ErrNotAllOf) Expected() interface{} { return e.Subschemas }
+func (e *ErrNotAllOf) Unwrap() []error       { return nonNilErrors(e.Errors) }
 
+func (e *ErrNotAllOf) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "value must be all of:")
+	formatSubschemaErrors(&buf, e.Subschemas, e.Errors)
 	return buf.String()
 }
 
 // ErrNotAnyOf is returned when a `anyOf` keyword failed.
 type ErrNotAnyOf struct {
-	Value   interface{}
-	Schemas []*Schema
-	Errors  []error
+	errorBase
+	Subschemas []*Schema
+	Errors     []error
 }
 
+func (e *ErrNotAnyOf) Code() ErrorCode       { return CodeNotAnyOf }
+func (e *ErrNotAnyOf) Expected() interface{} { return e.Subschemas }
+func (e *ErrNotAnyOf) Unwrap() []error       { return nonNilErrors(e.Errors) }
+
 func (e *ErrNotAnyOf) Error() string {
 	var buf bytes.Buffer
-
 	fmt.Fprintf(&buf, "value must be any of:")
+	formatSubschemaErrors(&buf, e.Subschemas, e.Errors)
+	return buf.String()
+}
 
-	for i, schema := range e.Schemas {
-		var (
-			err    = e.Errors[i]
-			errstr = "<nil>"
-		)
+// ErrNotOneOf is returned when a `oneOf` keyword failed. Errors has the
+// error of each of the Subschemas (nil for the valid ones).
+type ErrNotOneOf struct {
+	errorBase
+	Subschemas []*Schema
+	Errors     []error
+}
 
-		if err != nil {
-			errstr = strings.Replace(err.Error(), "\n", "\n    ", -1)
-		}
+func (e *ErrNotOneOf) Code() ErrorCode       { return CodeNotOneOf }
+func (e *ErrNotOneOf) Expected() interface{} { return e.Subschemas }
+func (e *ErrNotOneOf) Unwrap() []error       { return nonNilErrors(e.Errors) }
+
+func (e *ErrNotOneOf) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "value must be one of:")
+	formatSubschemaErrors(&buf, e.Subschemas, e.Errors)
+	return buf.String()
+}
+
+// ErrNotConst is returned when a `const` keyword failed.
+type ErrNotConst struct {
+	errorBase
+	Const interface{}
+}
+
+func (e *ErrNotConst) Code() ErrorCode       { return CodeNotConst }
+func (e *ErrNotConst) Expected() interface{} { return e.Const }
+
+func (e *ErrNotConst) Error() string {
+	return fmt.Sprintf("%v must be equal to %v", e.Value(), e.Const)
+}
+
+// ErrNotContains is returned when a `contains`, `minContains` or a
+// `maxContains` keyword failed. Max is -1 when there is no maximum.
+type ErrNotContains struct {
+	errorBase
+	Subschema *Schema
+	Count     int
+	Min       int
+	Max       int
+}
+
+func (e *ErrNotContains) Code() ErrorCode { return CodeNotContains }
+
+func (e *ErrNotContains) tooMany() bool { return e.Max >= 0 && e.Count > e.Max }
 
-		fmt.Fprintf(&buf, "\n- schema: %v\n  error:\n    %v", schema, errstr)
+func (e *ErrNotContains) Keyword() string {
+	if e.tooMany() {
+		return "maxContains"
 	}
+	if e.Min != 1 {
+		return "minContains"
+	}
+	return "contains"
+}
 
-	return buf.String()
+func (e *ErrNotContains) Expected() interface{} {
+	if e.tooMany() {
+		return e.Max
+	}
+	return e.Min
+}
+
+func (e *ErrNotContains) Error() string {
+	if e.tooMany() {
+		return fmt.Sprintf("value must contain at most %d items which are valid for: %v (found %d)", e.Max, e.Subschema, e.Count)
+	}
+	if e.Min != 1 {
+		return fmt.Sprintf("value must contain at least %d items which are valid for: %v (found %d)", e.Min, e.Subschema, e.Count)
+	}
+	return fmt.Sprintf("value must contain an item which is valid for: %v", e.Subschema)
 }
 
-// ErrInvalidDependency is returned when a `dependency` keyword failed.
+// ErrInvalidDependency is returned when a `dependency` keyword failed. Either
+// the Dependency property is missing or the value is not valid for the
+// Subschema.
 type ErrInvalidDependency struct {
+	errorBase
 	Property   string
 	Dependency string
-	Schema     *Schema
+	Subschema  *Schema
 	Err        error
 }
 
+func (e *ErrInvalidDependency) Code() ErrorCode { return CodeInvalidDependency }
+func (e *ErrInvalidDependency) Unwrap() error   { return e.Err }
+
+func (e *ErrInvalidDependency) Expected() interface{} {
+	if e.Subschema != nil {
+		return e.Subschema
+	}
+	return e.Dependency
+}
+
 func (e *ErrInvalidDependency) Error() string {
-	if e.Schema != nil {
+	if e.Subschema != nil {
 		return fmt.Sprintf("Invalid property %q: faile to validate dependecy: %s", e.Property, e.Err)
 	} else {
 		return fmt.Sprintf("Invalid property %q: missing property: %q", e.Property, e.Dependency)
@@ -116,191 +323,292 @@ func (e *ErrInvalidDependency) Error() string {
 
 // ErrInvalidEnum is returned when a `enum` keyword failed.
 type ErrInvalidEnum struct {
-	Expected []interface{}
-	Value    interface{}
+	errorBase
+	Enum []interface{}
 }
 
+func (e *ErrInvalidEnum) Code() ErrorCode       { return CodeInvalidEnum }
+func (e *ErrInvalidEnum) Expected() interface{} { return e.Enum }
+
 func (e *ErrInvalidEnum) Error() string {
-	return fmt.Sprintf("%v must be in %v", e.Value, e.Expected)
+	return fmt.Sprintf("%v must be in %v", e.Value(), e.Enum)
 }
 
 // ErrInvalidFormat is returned when a `format` keyword failed.
 type ErrInvalidFormat struct {
-	Value  interface{}
+	errorBase
 	Format string
 }
 
+func (e *ErrInvalidFormat) Code() ErrorCode       { return CodeInvalidFormat }
+func (e *ErrInvalidFormat) Expected() interface{} { return e.Format }
+
 func (e *ErrInvalidFormat) Error() string {
-	return fmt.Sprintf("%#v did not match format '%s'", e.Value, e.Format)
+	return fmt.Sprintf("%#v did not match format '%s'", e.Value(), e.Format)
 }
 
 // ErrInvalidItem is returned when a `item` keyword failed.
 type ErrInvalidItem struct {
+	errorBase
 	Index int
 	Err   error
 }
 
+func (e *ErrInvalidItem) Code() ErrorCode       { return CodeInvalidItem }
+func (e *ErrInvalidItem) Expected() interface{} { return nil }
+func (e *ErrInvalidItem) Unwrap() error         { return e.Err }
+
 func (e *ErrInvalidItem) Error() string {
 	return fmt.Sprintf("Invalid item at %v: %s", e.Index, e.Err)
 }
 
 // ErrTooLarge is returned when a `maximum` keyword failed.
 type ErrTooLarge struct {
-	max       float64
-	exclusive bool
-	was       interface{}
+	errorBase
+	Max       float64
+	Exclusive bool
 }
 
+func (e *ErrTooLarge) Code() ErrorCode       { return CodeTooLarge }
+func (e *ErrTooLarge) Expected() interface{} { return e.Max }
+
 func (e *ErrTooLarge) Error() string {
-	if e.exclusive {
-		return fmt.Sprintf("expected %#v to be smaller than %v", e.was, e.max)
+	if e.Exclusive {
+		return fmt.Sprintf("expected %#v to be smaller than %v", e.Value(), e.Max)
 	} else {
-		return fmt.Sprintf("expected %#v to be smaller than or equal to %v", e.was, e.max)
+		return fmt.Sprintf("expected %#v to be smaller than or equal to %v", e.Value(), e.Max)
 	}
 }
 
 // ErrTooLong is returned when a `maxLength`, `maxItems` or a `maxProperties` keyword failed.
 type ErrTooLong struct {
-	max int
-	was interface{}
+	errorBase
+	Max int
 }
 
+func (e *ErrTooLong) Code() ErrorCode       { return CodeTooLong }
+func (e *ErrTooLong) Expected() interface{} { return e.Max }
+
 func (e *ErrTooLong) Error() string {
-	return fmt.Sprintf("expected len(%#v) to be smaller than %v", e.was, e.max)
+	return fmt.Sprintf("expected len(%#v) to be smaller than %v", e.Value(), e.Max)
 }
 
 // ErrTooSmall is returned when a `minimum` keyword failed.
 type ErrTooSmall struct {
-	min       float64
-	exclusive bool
-	was       interface{}
+	errorBase
+	Min       float64
+	Exclusive bool
 }
 
+func (e *ErrTooSmall) Code() ErrorCode       { return CodeTooSmall }
+func (e *ErrTooSmall) Expected() interface{} { return e.Min }
+
 func (e *ErrTooSmall) Error() string {
-	if e.exclusive {
-		return fmt.Sprintf("expected %#v to be larger than %v", e.was, e.min)
+	if e.Exclusive {
+		return fmt.Sprintf("expected %#v to be larger than %v", e.Value(), e.Min)
 	} else {
-		return fmt.Sprintf("expected %#v to be larger than or equal to %v", e.was, e.min)
+		return fmt.Sprintf("expected %#v to be larger than or equal to %v", e.Value(), e.Min)
 	}
 }
 
 // ErrTooShort is returned when a `minLength`, `minItems` or a `minProperties` keyword failed.
 type ErrTooShort struct {
-	min int
-	was interface{}
+	errorBase
+	Min int
 }
 
+func (e *ErrTooShort) Code() ErrorCode       { return CodeTooShort }
+func (e *ErrTooShort) Expected() interface{} { return e.Min }
+
 func (e *ErrTooShort) Error() string {
-	return fmt.Sprintf("expected len(%#v) to be larger than %v", e.was, e.min)
+	return fmt.Sprintf("expected len(%#v) to be larger than %v", e.Value(), e.Min)
 }
 
 // ErrNotMultipleOf is returned when a `multipleOf` keyword failed.
 type ErrNotMultipleOf struct {
-	factor float64
-	was    interface{}
+	errorBase
+	Factor float64
 }
 
+func (e *ErrNotMultipleOf) Code() ErrorCode       { return CodeNotMultipleOf }
+func (e *ErrNotMultipleOf) Expected() interface{} { return e.Factor }
+
 func (e *ErrNotMultipleOf) Error() string {
-	return fmt.Sprintf("expected %#v to be a multiple of %v", e.was, e.factor)
+	return fmt.Sprintf("expected %#v to be a multiple of %v", e.Value(), e.Factor)
 }
 
 // ErrNotNot is returned when a `not` keyword failed.
 type ErrNotNot struct {
-	Value  interface{}
-	Schema *Schema
+	errorBase
+	Subschema *Schema
 }
 
+func (e *ErrNotNot) Code() ErrorCode       { return CodeNotNot }
+func (e *ErrNotNot) Expected() interface{} { return e.Subschema }
+
 func (e *ErrNotNot) Error() string {
-	return fmt.Sprintf("value must not be valid for: %v", e.Schema)
+	return fmt.Sprintf("value must not be valid for: %v", e.Subschema)
 }
 
-// ErrNotOneOf is returned when a `oneOf` keyword failed.
-type ErrNotOneOf struct {
-	Value   interface{}
-	Schemas []*Schema
-	Errors  []error
+// ErrNotThen is returned when a `then` keyword failed.
+type ErrNotThen struct {
+	errorBase
+	Subschema *Schema
+	Err       error
 }
 
-func (e *ErrNotOneOf) Error() string {
-	var buf bytes.Buffer
+func (e *ErrNotThen) Code() ErrorCode       { return CodeNotThen }
+func (e *ErrNotThen) Keyword() string       { return "then" }
+func (e *ErrNotThen) Expected() interface{} { return e.Subschema }
+func (e *ErrNotThen) Unwrap() error         { return e.Err }
 
-	fmt.Fprintf(&buf, "value must be one of:")
-
-	for i, schema := range e.Schemas {
-		var (
-			err    = e.Errors[i]
-			errstr = "<nil>"
-		)
+func (e *ErrNotThen) Error() string {
+	return fmt.Sprintf("value matches 'if' but not 'then': %s", e.Err)
+}
 
-		if err != nil {
-			errstr = strings.Replace(err.Error(), "\n", "\n    ", -1)
-		}
+// ErrNotElse is returned when a `else` keyword failed.
+type ErrNotElse struct {
+	errorBase
+	Subschema *Schema
+	Err       error
+}
 
-		fmt.Fprintf(&buf, "\n- schema: %v\n  error:\n    %v", schema, errstr)
-	}
+func (e *ErrNotElse) Code() ErrorCode       { return CodeNotElse }
+func (e *ErrNotElse) Keyword() string       { return "else" }
+func (e *ErrNotElse) Expected() interface{} { return e.Subschema }
+func (e *ErrNotElse) Unwrap() error         { return e.Err }
 
-	return buf.String()
+func (e *ErrNotElse) Error() string {
+	return fmt.Sprintf("value does not match 'if' nor 'else': %s", e.Err)
 }
 
 // ErrInvalidPattern is returned when a `pattern` keyword failed.
 type ErrInvalidPattern struct {
-	pattern string
-	was     string
+	errorBase
+	Pattern string
 }
 
+func (e *ErrInvalidPattern) Code() ErrorCode       { return CodeInvalidPattern }
+func (e *ErrInvalidPattern) Expected() interface{} { return e.Pattern }
+
 func (e *ErrInvalidPattern) Error() string {
-	return fmt.Sprintf("expected %#v to be maych %q", e.was, e.pattern)
+	return fmt.Sprintf("expected %#v to be maych %q", e.Value(), e.Pattern)
 }
 
 // ErrInvalidProperty is returned when a `property` keyword failed.
 type ErrInvalidProperty struct {
+	errorBase
 	Property string
 	Err      error
 }
 
+func (e *ErrInvalidProperty) Code() ErrorCode       { return CodeInvalidProperty }
+func (e *ErrInvalidProperty) Expected() interface{} { return nil }
+func (e *ErrInvalidProperty) Unwrap() error         { return e.Err }
+
 func (e *ErrInvalidProperty) Error() string {
 	return fmt.Sprintf("Invalid property %q: %s", e.Property, e.Err)
 }
 
+// ErrInvalidPropertyName is returned when a `propertyNames` keyword failed.
+type ErrInvalidPropertyName struct {
+	errorBase
+	Property string
+	Err      error
+}
+
+func (e *ErrInvalidPropertyName) Code() ErrorCode       { return CodeInvalidPropertyName }
+func (e *ErrInvalidPropertyName) Expected() interface{} { return nil }
+func (e *ErrInvalidPropertyName) Unwrap() error         { return e.Err }
+
+func (e *ErrInvalidPropertyName) Error() string {
+	return fmt.Sprintf("Invalid property name %q: %s", e.Property, e.Err)
+}
+
 // ErrRequiredProperty is returned when a `required` keyword failed.
 type ErrRequiredProperty struct {
-	expected string
+	errorBase
+	Property string
 }
 
+func (e *ErrRequiredProperty) Code() ErrorCode       { return CodeRequiredProperty }
+func (e *ErrRequiredProperty) Expected() interface{} { return e.Property }
+
 func (e *ErrRequiredProperty) Error() string {
-	return fmt.Sprintf("missing required property: %q", e.expected)
+	return fmt.Sprintf("missing required property: %q", e.Property)
 }
 
 // ErrInvalidType is returned when a `type` keyword failed.
 type ErrInvalidType struct {
-	expected []PrimitiveType
-	was      interface{}
+	errorBase
+	Types []PrimitiveType
 }
 
+func (e *ErrInvalidType) Code() ErrorCode       { return CodeInvalidType }
+func (e *ErrInvalidType) Expected() interface{} { return e.Types }
+
 func (e *ErrInvalidType) Error() string {
-	return fmt.Sprintf("expected type to be in %#v but was %#v", e.expected, e.was)
+	return fmt.Sprintf("expected type to be in %#v but was %#v", e.Types, e.Value())
+}
+
+// ErrDisallowedType is returned when a `disallow` keyword failed.
+type ErrDisallowedType struct {
+	errorBase
+}
+
+func (e *ErrDisallowedType) Code() ErrorCode       { return CodeDisallowedType }
+func (e *ErrDisallowedType) Expected() interface{} { return nil }
+
+func (e *ErrDisallowedType) Error() string {
+	return fmt.Sprintf("type of %#v is not allowed", e.Value())
 }
 
 // ErrNotUnique is returned when a `uniqueItems` keyword failed.
 type ErrNotUnique struct {
+	errorBase
 	IndexA int
 	IndexB int
-	Value  interface{}
+	Item   interface{}
 }
 
+func (e *ErrNotUnique) Code() ErrorCode       { return CodeNotUnique }
+func (e *ErrNotUnique) Expected() interface{} { return true }
+
 func (e *ErrNotUnique) Error() string {
-	return fmt.Sprintf("value at %d (%v) is not unique (repeated at %d)", e.IndexA, e.Value, e.IndexB)
+	return fmt.Sprintf("value at %d (%v) is not unique (repeated at %d)", e.IndexA, e.Item, e.IndexB)
 }
 
-// ErrInvalidInstance is returned when the instance is invalid.
+// ErrFalseSchema is returned when a value is validated against the boolean
+// schema `false`.
+type ErrFalseSchema struct {
+	errorBase
+}
+
+func (e *ErrFalseSchema) Code() ErrorCode       { return CodeFalseSchema }
+func (e *ErrFalseSchema) Expected() interface{} { return nil }
+
+func (e *ErrFalseSchema) Error() string {
+	return fmt.Sprintf("value is not allowed: %v", e.Value())
+}
+
+// ErrInvalidInstance is returned when the instance is invalid. It holds the
+// errors of the keywords of its Schema.
 type ErrInvalidInstance struct {
-	Schema *Schema
+	errorBase
 	Errors []error
 }
 
+func (e *ErrInvalidInstance) Code() ErrorCode       { return CodeInvalidInstance }
+func (e *ErrInvalidInstance) Expected() interface{} { return nil }
+func (e *ErrInvalidInstance) Unwrap() []error       { return e.Errors }
+
 func (e *ErrInvalidInstance) Error() string {
 	var buf bytes.Buffer
-	fmt.Fprintf(&buf, "Schema errors (%s):", normalizeRef(e.Schema.Id.String()))
+	id := "#"
+	if s := e.Schema(); s != nil {
+		id = normalizeRef(s.Id.String())
+	}
+	fmt.Fprintf(&buf, "Schema errors (%s):", id)
 	for _, err := range e.Errors {
 		s := strings.Replace(err.Error(), "\n", "\n  ", -1)
 		fmt.Fprintf(&buf, "\n- %s", s)
@@ -0,0 +1,18 @@
+package jsonschema
+
+import (
+	"encoding/base64"
+)
+
+// byteFormat implements the OpenAPI `byte` format (base64 encoded data).
+type byteFormat struct{}
+
+func (*byteFormat) IsValid(x interface{}) bool {
+	s, ok := x.(string)
+	if !ok {
+		return true
+	}
+
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,33 @@
+package jsonschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// colorFormat implements the draft-03 `color` format (a CSS 2.1 color).
+type colorFormat struct{}
+
+var (
+	colorHexRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	colorRGBRegexp = regexp.MustCompile(`^rgb\(\s*(\d{1,3}%?)\s*,\s*(\d{1,3}%?)\s*,\s*(\d{1,3}%?)\s*\)$`)
+
+	colorNames = map[string]bool{
+		"aqua": true, "black": true, "blue": true, "fuchsia": true,
+		"gray": true, "green": true, "lime": true, "maroon": true,
+		"navy": true, "olive": true, "orange": true, "purple": true,
+		"red": true, "silver": true, "teal": true, "white": true,
+		"yellow": true,
+	}
+)
+
+func (*colorFormat) IsValid(x interface{}) bool {
+	s, ok := x.(string)
+	if !ok {
+		return true
+	}
+
+	return colorNames[strings.ToLower(s)] ||
+		colorHexRegexp.MatchString(s) ||
+		colorRGBRegexp.MatchString(s)
+}
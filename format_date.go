@@ -0,0 +1,17 @@
+package jsonschema
+
+import (
+	"time"
+)
+
+type dateFormat struct{}
+
+func (*dateFormat) IsValid(x interface{}) bool {
+	s, ok := x.(string)
+	if !ok {
+		return true
+	}
+
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
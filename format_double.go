@@ -0,0 +1,8 @@
+package jsonschema
+
+type doubleFormat struct{}
+
+func (*doubleFormat) IsValid(x interface{}) bool {
+	_, ok, err := toFloat(x)
+	return !ok || err == nil
+}
@@ -0,0 +1,16 @@
+package jsonschema
+
+import (
+	"math"
+)
+
+type floatFormat struct{}
+
+func (*floatFormat) IsValid(x interface{}) bool {
+	f, ok, err := toFloat(x)
+	if !ok {
+		return true
+	}
+
+	return err == nil && math.Abs(f) <= math.MaxFloat32
+}
@@ -0,0 +1,16 @@
+package jsonschema
+
+import (
+	"math"
+)
+
+type int32Format struct{}
+
+func (*int32Format) IsValid(x interface{}) bool {
+	f, ok, err := toFloat(x)
+	if !ok {
+		return true
+	}
+
+	return err == nil && f >= math.MinInt32 && f <= math.MaxInt32
+}
@@ -0,0 +1,23 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math"
+)
+
+type int64Format struct{}
+
+func (*int64Format) IsValid(x interface{}) bool {
+	if y, ok := x.(json.Number); ok {
+		if _, err := y.Int64(); err == nil {
+			return true
+		}
+	}
+
+	f, ok, err := toFloat(x)
+	if !ok {
+		return true
+	}
+
+	return err == nil && f >= math.MinInt64 && f <= math.MaxInt64
+}
@@ -0,0 +1,31 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+type jsonPointerFormat struct{}
+
+func (*jsonPointerFormat) IsValid(x interface{}) bool {
+	s, ok := x.(string)
+	if !ok {
+		return true
+	}
+
+	if s == "" {
+		return true
+	}
+
+	if s[0] != '/' {
+		return false
+	}
+
+	for i := strings.IndexByte(s, '~'); i >= 0; i = strings.IndexByte(s, '~') {
+		if i+1 >= len(s) || (s[i+1] != '0' && s[i+1] != '1') {
+			return false
+		}
+		s = s[i+2:]
+	}
+
+	return true
+}
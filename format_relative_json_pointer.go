@@ -0,0 +1,28 @@
+package jsonschema
+
+type relativeJSONPointerFormat struct {
+	pointer jsonPointerFormat
+}
+
+func (f *relativeJSONPointerFormat) IsValid(x interface{}) bool {
+	s, ok := x.(string)
+	if !ok {
+		return true
+	}
+
+	i := 0
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+
+	if i == 0 || (i > 1 && s[0] == '0') {
+		return false
+	}
+
+	s = s[i:]
+	if s == "#" {
+		return true
+	}
+
+	return f.pointer.IsValid(s)
+}
@@ -0,0 +1,31 @@
+package jsonschema
+
+import (
+	"time"
+)
+
+type timeFormat struct{}
+
+func (*timeFormat) IsValid(x interface{}) bool {
+	s, ok := x.(string)
+	if !ok {
+		return true
+	}
+
+	_, err := time.Parse("15:04:05Z07:00", s)
+	return err == nil
+}
+
+// localTimeFormat implements the draft-03 `time` format (hh:mm:ss without a
+// time zone offset).
+type localTimeFormat struct{}
+
+func (*localTimeFormat) IsValid(x interface{}) bool {
+	s, ok := x.(string)
+	if !ok {
+		return true
+	}
+
+	_, err := time.Parse("15:04:05", s)
+	return err == nil
+}
@@ -0,0 +1,33 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+type uriTemplateFormat struct{}
+
+func (*uriTemplateFormat) IsValid(x interface{}) bool {
+	s, ok := x.(string)
+	if !ok {
+		return true
+	}
+
+	for {
+		open := strings.IndexByte(s, '{')
+		close := strings.IndexByte(s, '}')
+
+		if open < 0 {
+			return close < 0
+		}
+
+		if close < open {
+			return false
+		}
+
+		if strings.IndexByte(s[open+1:close], '{') >= 0 || close == open+1 {
+			return false
+		}
+
+		s = s[close+1:]
+	}
+}
@@ -0,0 +1,10 @@
+package jsonschema
+
+// utcMillisecFormat implements the draft-03 `utc-millisec` format (the
+// number of milliseconds since the unix epoch).
+type utcMillisecFormat struct{}
+
+func (*utcMillisecFormat) IsValid(x interface{}) bool {
+	_, ok, err := toFloat(x)
+	return !ok || err == nil
+}
@@ -0,0 +1,1070 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoCompiler generates Go functions which validate values against schemas.
+// The functions do what the validators of the schemas do, without walking
+// the validators, and they report the same errors (with the same values and
+// locations) as Schema.Validate.
+//
+// Like Schema.Validate the functions validate any Go value (like the values
+// decoded by encoding/json or a struct) and they never modify the value.
+// Objects and arrays (map[string]interface{} and []interface{}) must hold
+// values decoded by encoding/json; other values are converted first.
+// The keywords of draft-04 to draft-07 (and of the OpenAPI 3.0 schema
+// object) are supported; Generate fails for schemas with other keywords.
+//
+// The generated code calls the Compiled functions of this package, which
+// are internal to the generated code: it must be generated again with the
+// version of the package it is built with.
+type GoCompiler struct {
+	// Package is the name of the package of the generated file.
+	Package string
+
+	funcs   []compiledFunc
+	names   map[*Schema]string
+	queue   []*Schema
+	vars    map[string]string
+	schemas map[*Schema]string
+	decls   bytes.Buffer
+	imports map[string]bool
+}
+
+type compiledFunc struct {
+	name   string
+	schema *Schema
+}
+
+// NewGoCompiler returns a compiler for the package pkg.
+func NewGoCompiler(pkg string) *GoCompiler {
+	return &GoCompiler{
+		Package: pkg,
+		names:   map[*Schema]string{},
+		vars:    map[string]string{},
+		schemas: map[*Schema]string{},
+		imports: map[string]bool{},
+	}
+}
+
+// AddFunc adds a function named name (converted to a Go identifier) which
+// validates a value against schema:
+//
+//	func Name(x interface{}) error
+func (c *GoCompiler) AddFunc(name string, schema *Schema) {
+	c.funcs = append(c.funcs, compiledFunc{goName(name), schema})
+}
+
+// Generate returns the formatted source of the functions.
+func (c *GoCompiler) Generate() ([]byte, error) {
+	var (
+		buf   bytes.Buffer
+		funcs bytes.Buffer
+		pkg   = reflect.TypeOf(Schema{}).PkgPath()
+	)
+
+	c.imports["strconv"] = true
+	c.imports["strings"] = true
+	c.imports[pkg] = true
+
+	for _, f := range c.funcs {
+		target, err := c.resolve(f.schema)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", f.name, err)
+		}
+
+		if f.schema.Id != nil && f.schema.Id.String() != "" {
+			fmt.Fprintf(&funcs, "// %s validates x against %s.\n", f.name, f.schema.Id)
+		} else {
+			fmt.Fprintf(&funcs, "// %s validates x.\n", f.name)
+		}
+		fmt.Fprintf(&funcs, "func %s(x interface{}) error {\n", f.name)
+		fmt.Fprintf(&funcs, "_, _, err := %s(jsonschema.CompiledValue(x), false, &location{keyword: %q})\n", c.funcName(target), compiledKeywordLocation(nil, "", f.schema))
+		fmt.Fprintf(&funcs, "return err\n}\n\n")
+	}
+
+	for len(c.queue) > 0 {
+		schema := c.queue[0]
+		c.queue = c.queue[1:]
+
+		err := c.compile(schema)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", schema.Id, err)
+		}
+	}
+
+	buf.WriteString("// Code generated by jsonschema-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", c.Package)
+
+	imports := make([]string, 0, len(c.imports))
+	for path := range c.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+
+	buf.WriteString("import (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.Write(funcs.Bytes())
+
+	names := make([]string, 0, len(c.vars))
+	for name := range c.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf.WriteString("var (\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s = %s\n", name, c.vars[name])
+	}
+	buf.WriteString(")\n\n")
+
+	buf.Write(c.decls.Bytes())
+	buf.WriteString(compiledRuntime)
+	if c.imports["net/url"] {
+		buf.WriteString(compiledParseURL)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %s", err)
+	}
+	return src, nil
+}
+
+// resolve follows the references of schema (like the Context does).
+func (c *GoCompiler) resolve(schema *Schema) (*Schema, error) {
+	for schema.RefSchema != nil {
+		schema = schema.RefSchema
+	}
+	if schema.Ref != nil {
+		return nil, fmt.Errorf("unresolved reference: %s", schema.Ref)
+	}
+	return schema, nil
+}
+
+// funcName returns the name of the function which validates with schema
+// (which must be resolved).
+func (c *GoCompiler) funcName(schema *Schema) string {
+	if name, found := c.names[schema]; found {
+		return name
+	}
+
+	name := "validate" + strconv.Itoa(len(c.names))
+	c.names[schema] = name
+	c.queue = append(c.queue, schema)
+	return name
+}
+
+// schemaVar returns the variable which holds the stand-in for schema in the
+// reported errors.
+func (c *GoCompiler) schemaVar(schema *Schema) string {
+	if name, found := c.schemas[schema]; found {
+		return name
+	}
+
+	name := "schema" + strconv.Itoa(len(c.schemas))
+	c.schemas[schema] = name
+
+	if schema.Id != nil {
+		c.imports["net/url"] = true
+		c.vars[name] = fmt.Sprintf("&jsonschema.Schema{Id: mustParseURL(%q)}", schema.Id.String())
+	} else {
+		c.vars[name] = "&jsonschema.Schema{}"
+	}
+	return name
+}
+
+// newVar declares a variable with the value expr (reusing the variable of
+// an identical expression).
+func (c *GoCompiler) newVar(prefix, expr string) string {
+	for name, x := range c.vars {
+		if x == expr && strings.HasPrefix(name, prefix) {
+			return name
+		}
+	}
+
+	name := prefix + strconv.Itoa(len(c.vars))
+	c.vars[name] = expr
+	return name
+}
+
+// compiledKeyword is the keyword of the validator being compiled.
+type compiledKeyword struct {
+	schema  *Schema
+	self    string
+	keyword string
+}
+
+// report returns the statement which reports the error err.
+func (k *compiledKeyword) report(err string) string {
+	return fmt.Sprintf("errs = report(errs, %s, %q, x, %s, loc)\n", err, k.keyword, k.self)
+}
+
+// child returns the expression of the location of the subschema via
+// (applied to the value at token).
+func (k *compiledKeyword) child(via *Schema, token string) string {
+	location := compiledKeywordLocation(k.schema, k.keyword, via)
+	if token == "" {
+		return fmt.Sprintf("&location{parent: loc, keyword: %q}", location)
+	}
+	return fmt.Sprintf("&location{parent: loc, %s, hasToken: true, keyword: %q}", token, location)
+}
+
+// compiledKeywordLocation returns the location of the schema via relative
+// to its parent schema (see Context.relativeKeywordLocation).
+func compiledKeywordLocation(parent *Schema, keyword string, via *Schema) string {
+	var location string
+
+	if parent != nil {
+		if via.parent == parent && via.pointer != "" {
+			location = via.pointer
+		} else {
+			location = joinKeywordLocation("", keyword)
+		}
+	}
+
+	for s := via; s.RefSchema != nil; s = s.RefSchema {
+		if s.pointer != "" || s.parent == nil {
+			location += "/$ref"
+		}
+	}
+
+	return location
+}
+
+// subschema returns the name of the function for the subschema via. The
+// in-place subschemas (which validate the same value) must not lead back to
+// the schema (the Context rejects those loops).
+func (c *GoCompiler) subschema(schema, via *Schema, inPlace bool) (string, error) {
+	target, err := c.resolve(via)
+	if err != nil {
+		return "", err
+	}
+
+	if inPlace && c.loops(target, schema, map[*Schema]bool{}) {
+		return "", fmt.Errorf("schema validation loops are invalid")
+	}
+
+	return c.funcName(target), nil
+}
+
+// loops returns true when schema reaches target through in-place
+// subschemas.
+func (c *GoCompiler) loops(schema, target *Schema, seen map[*Schema]bool) bool {
+	if schema == target {
+		return true
+	}
+	if seen[schema] {
+		return false
+	}
+	seen[schema] = true
+
+	for _, validator := range schema.Validators {
+		var schemas []*Schema
+		switch v := validator.(type) {
+		case *allOfValidator:
+			schemas = v.schemas
+		case *anyOfValidator:
+			schemas = v.schemas
+		case *oneOfValidator:
+			schemas = v.schemas
+		case *notValidator:
+			schemas = []*Schema{v.schema}
+		case *refValidator:
+			schemas = []*Schema{v.schema}
+		}
+
+		for _, s := range schemas {
+			for s.RefSchema != nil {
+				s = s.RefSchema
+			}
+			if c.loops(s, target, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *GoCompiler) compile(schema *Schema) error {
+	if schema.tracksEvaluation {
+		return fmt.Errorf("unsupported keyword: unevaluatedProperties/unevaluatedItems")
+	}
+
+	var (
+		buf  bytes.Buffer
+		self = c.schemaVar(schema)
+	)
+
+	fmt.Fprintf(&buf, "func %s(x interface{}, owned bool, loc *location) (interface{}, bool, error) {\n", c.names[schema])
+	buf.WriteString("var errs []error\n\n")
+
+	for i, validator := range schema.Validators {
+		k := &compiledKeyword{schema: schema, self: self}
+		if i < len(schema.keywords) {
+			k.keyword = schema.keywords[i]
+		}
+
+		err := c.compileValidator(&buf, k, validator)
+		if err != nil {
+			return err
+		}
+	}
+
+	buf.WriteString("if errs != nil {\n")
+	fmt.Fprintf(&buf, "return x, owned, jsonschema.CompiledReport(&jsonschema.ErrInvalidInstance{Errors: errs}, \"\", x, %s, loc.instanceLocation(), loc.keywordLocation())\n", self)
+	buf.WriteString("}\n")
+	buf.WriteString("return x, owned, nil\n}\n\n")
+
+	c.decls.Write(buf.Bytes())
+	return nil
+}
+
+func (c *GoCompiler) compileValidator(buf *bytes.Buffer, k *compiledKeyword, validator Validator) error {
+	switch v := validator.(type) {
+	case *definitionsValidator, *defsValidator, *annotationValidator:
+		return nil
+
+	case *falseValidator:
+		buf.WriteString(k.report("&jsonschema.ErrFalseSchema{}"))
+
+	case *typeValidator:
+		c.compileType(buf, k, v.expects, false)
+
+	case *integralTypeValidator:
+		c.compileType(buf, k, v.expects, true)
+
+	case *nullableTypeValidator:
+		if len(v.expects) == 0 {
+			return nil
+		}
+		if v.nullable {
+			buf.WriteString("if x != nil {\n")
+		}
+		c.compileType(buf, k, v.expects, false)
+		if v.nullable {
+			buf.WriteString("}\n")
+		}
+
+	case *enumValidator:
+		enum, err := c.literal(v.enum)
+		if err != nil {
+			return err
+		}
+		name := c.newVar("enum", enum)
+
+		buf.WriteString("{\nmatched := false\n")
+		fmt.Fprintf(buf, "for _, y := range %s {\n", name)
+		buf.WriteString("equal, err := jsonschema.CompiledEqual(x, y)\n")
+		buf.WriteString("if err != nil {\nerrs = append(errs, err)\n}\n")
+		buf.WriteString("if equal {\nmatched = true\nbreak\n}\n}\n")
+		buf.WriteString("if !matched {\n")
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrInvalidEnum{Enum: %s}", name)))
+		buf.WriteString("}\n}\n")
+
+	case *constValidator:
+		value, err := c.literal(v.value)
+		if err != nil {
+			return err
+		}
+		name := c.newVar("const", value)
+
+		fmt.Fprintf(buf, "if equal, err := jsonschema.CompiledEqual(x, %s); err != nil {\nerrs = append(errs, err)\n} else if !equal {\n", name)
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrNotConst{Const: %s}", name)))
+		buf.WriteString("}\n")
+
+	case *allOfValidator:
+		buf.WriteString("{\nvar suberrs []error\n")
+		for i, schema := range v.schemas {
+			f, err := c.subschema(k.schema, schema, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(buf, "if y, o, err := %s(x, owned, %s); err != nil {\n", f, k.child(schema, ""))
+			fmt.Fprintf(buf, "if suberrs == nil {\nsuberrs = make([]error, %d)\n}\n", len(v.schemas))
+			fmt.Fprintf(buf, "suberrs[%d] = err\n} else {\nx, owned = y, o\n}\n", i)
+		}
+		buf.WriteString("if suberrs != nil {\n")
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrNotAllOf{Subschemas: %s, Errors: suberrs}", c.schemaList(v.schemas))))
+		buf.WriteString("}\n}\n")
+
+	case *anyOfValidator:
+		buf.WriteString("{\nvar suberrs []error\npassed := false\n")
+		for i, schema := range v.schemas {
+			f, err := c.subschema(k.schema, schema, true)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				buf.WriteString("if !passed {\n")
+			}
+			fmt.Fprintf(buf, "if y, o, err := %s(x, owned, %s); err == nil {\n", f, k.child(schema, ""))
+			buf.WriteString("x, owned, passed = y, o, true\n} else {\n")
+			fmt.Fprintf(buf, "if suberrs == nil {\nsuberrs = make([]error, %d)\n}\n", len(v.schemas))
+			fmt.Fprintf(buf, "suberrs[%d] = err\n}\n", i)
+			if i > 0 {
+				buf.WriteString("}\n")
+			}
+		}
+		buf.WriteString("if !passed {\n")
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrNotAnyOf{Subschemas: %s, Errors: suberrs}", c.schemaList(v.schemas))))
+		buf.WriteString("}\n}\n")
+
+	case *oneOfValidator:
+		buf.WriteString("{\nvar suberrs []error\npassed := 0\n")
+		if len(v.schemas) > 0 {
+			fmt.Fprintf(buf, "suberrs = make([]error, %d)\n", len(v.schemas))
+		}
+		for i, schema := range v.schemas {
+			f, err := c.subschema(k.schema, schema, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(buf, "if y, o, err := %s(x, owned, %s); err == nil {\n", f, k.child(schema, ""))
+			buf.WriteString("x, owned = y, o\npassed++\n} else {\n")
+			fmt.Fprintf(buf, "suberrs[%d] = err\n}\n", i)
+		}
+		buf.WriteString("if passed != 1 {\n")
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrNotOneOf{Subschemas: %s, Errors: suberrs}", c.schemaList(v.schemas))))
+		buf.WriteString("}\n}\n")
+
+	case *notValidator:
+		f, err := c.subschema(k.schema, v.schema, true)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "if y, o, err := %s(x, owned, %s); err == nil {\n", f, k.child(v.schema, ""))
+		buf.WriteString("x, owned = y, o\n")
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrNotNot{Subschema: %s}", c.schemaVar(v.schema))))
+		buf.WriteString("}\n")
+
+	case *refValidator:
+		f, err := c.subschema(k.schema, v.schema, true)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "if y, o, err := %s(x, owned, %s); err != nil {\n", f, k.child(v.schema, ""))
+		buf.WriteString("errs = append(errs, err)\n} else {\nx, owned = y, o\n}\n")
+
+	case *annotatingFormatValidator:
+		// format is only an annotation (compiled validators take no
+		// ValidateOption).
+		return nil
+
+	case *openFormatValidator:
+		if v.format == nil {
+			return nil
+		}
+		return c.compileFormat(buf, k, &v.formatValidator)
+
+	case *formatValidator:
+		return c.compileFormat(buf, k, v)
+
+	case *multipleOfValidator:
+		f := formatFloat(v.factor)
+		c.compileNumber(buf, k, fmt.Sprintf("rem := math.Abs(math.Remainder(f, %s)) / %s; !(rem < 0.000000001)", f, f),
+			fmt.Sprintf("&jsonschema.ErrNotMultipleOf{Factor: %s}", f))
+
+	case *divisibleByValidator:
+		return fmt.Errorf("unsupported keyword: %s", k.keyword)
+
+	case *maximumValidator:
+		op := "<="
+		if v.exclusive {
+			op = "<"
+		}
+		c.compileNumber(buf, k, fmt.Sprintf("!(f %s %s)", op, formatFloat(v.max)),
+			fmt.Sprintf("&jsonschema.ErrTooLarge{Max: %s, Exclusive: %v}", formatFloat(v.max), v.exclusive))
+
+	case *minimumValidator:
+		op := ">="
+		if v.exclusive {
+			op = ">"
+		}
+		c.compileNumber(buf, k, fmt.Sprintf("!(f %s %s)", op, formatFloat(v.min)),
+			fmt.Sprintf("&jsonschema.ErrTooSmall{Min: %s, Exclusive: %v}", formatFloat(v.min), v.exclusive))
+
+	case *exclusiveMaximumValidator:
+		c.compileNumber(buf, k, fmt.Sprintf("f >= %s", formatFloat(v.max)),
+			fmt.Sprintf("&jsonschema.ErrTooLarge{Max: %s, Exclusive: true}", formatFloat(v.max)))
+
+	case *exclusiveMinimumValidator:
+		c.compileNumber(buf, k, fmt.Sprintf("f <= %s", formatFloat(v.min)),
+			fmt.Sprintf("&jsonschema.ErrTooSmall{Min: %s, Exclusive: true}", formatFloat(v.min)))
+
+	case *maxLengthValidator:
+		c.imports["unicode/utf8"] = true
+		fmt.Fprintf(buf, "if y, ok := x.(string); ok && utf8.RuneCountInString(y) > %d {\n", v.max)
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrTooLong{Max: %d}", v.max)))
+		buf.WriteString("}\n")
+
+	case *minLengthValidator:
+		c.imports["unicode/utf8"] = true
+		fmt.Fprintf(buf, "if y, ok := x.(string); ok && utf8.RuneCountInString(y) < %d {\n", v.min)
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrTooShort{Min: %d}", v.min)))
+		buf.WriteString("}\n")
+
+	case *patternValidator:
+		name := c.regexp(v.pattern)
+		fmt.Fprintf(buf, "if y, ok := x.(string); ok && !%s.MatchString(y) {\n", name)
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrInvalidPattern{Pattern: %q}", v.pattern)))
+		buf.WriteString("}\n")
+
+	case *maxItemsValidator:
+		fmt.Fprintf(buf, "if y, ok := x.([]interface{}); ok && y != nil && len(y) > %d {\n", v.max)
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrTooLong{Max: %d}", v.max)))
+		buf.WriteString("}\n")
+
+	case *minItemsValidator:
+		fmt.Fprintf(buf, "if y, ok := x.([]interface{}); ok && y != nil && len(y) < %d {\n", v.min)
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrTooShort{Min: %d}", v.min)))
+		buf.WriteString("}\n")
+
+	case *uniqueItemsValidator:
+		if !v.unique {
+			return nil
+		}
+		buf.WriteString("if y, ok := x.([]interface{}); ok && y != nil {\n")
+		buf.WriteString("for _, err := range jsonschema.CompiledUniqueItems(y) {\n")
+		buf.WriteString(k.report("err"))
+		buf.WriteString("}\n}\n")
+
+	case *itemsValidator:
+		return c.compileItems(buf, k, v)
+
+	case *maxPropertiesValidator:
+		fmt.Fprintf(buf, "if y, ok := x.(map[string]interface{}); ok && y != nil && len(y) > %d {\n", v.max)
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrTooLong{Max: %d}", v.max)))
+		buf.WriteString("}\n")
+
+	case *minPropertiesValidator:
+		fmt.Fprintf(buf, "if y, ok := x.(map[string]interface{}); ok && y != nil && len(y) < %d {\n", v.min)
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrTooShort{Min: %d}", v.min)))
+		buf.WriteString("}\n")
+
+	case *requiredValidator:
+		if len(v.required) == 0 {
+			return nil
+		}
+		buf.WriteString("if y, ok := x.(map[string]interface{}); ok && y != nil {\n")
+		for _, name := range v.required {
+			fmt.Fprintf(buf, "if _, found := y[%q]; !found {\n", name)
+			buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrRequiredProperty{Property: %q}", name)))
+			buf.WriteString("}\n")
+		}
+		buf.WriteString("}\n")
+
+	case *propertiesValidator:
+		return c.compileProperties(buf, k, v)
+
+	case *dependenciesValidator:
+		return c.compileDependencies(buf, k, v)
+
+	case *propertyNamesValidator:
+		f, err := c.subschema(k.schema, v.schema, false)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("if y, ok := x.(map[string]interface{}); ok && y != nil {\n")
+		buf.WriteString("for k := range y {\n")
+		fmt.Fprintf(buf, "if _, _, err := %s(k, false, %s); err != nil {\n", f, k.child(v.schema, ""))
+		buf.WriteString(k.report("&jsonschema.ErrInvalidPropertyName{Property: k, Err: err}"))
+		buf.WriteString("}\n}\n}\n")
+
+	default:
+		return fmt.Errorf("unsupported keyword: %s", k.keyword)
+	}
+
+	return nil
+}
+
+// compileType mirrors typeValidator.validate (and matchType) including the
+// conversion of the numbers.
+func (c *GoCompiler) compileType(buf *bytes.Buffer, k *compiledKeyword, expects []PrimitiveType, integral bool) {
+	buf.WriteString("{\nmatched := false\n")
+
+	types := make([]string, len(expects))
+	for i, t := range expects {
+		types[i] = strconv.Quote(string(t))
+
+		if i > 0 {
+			buf.WriteString("if !matched {\n")
+		}
+
+		switch t {
+		case ArrayType:
+			buf.WriteString("if _, ok := x.([]interface{}); ok {\nmatched = true\n}\n")
+
+		case BooleanType:
+			buf.WriteString("if _, ok := x.(bool); ok {\nmatched = true\n}\n")
+
+		case IntegerType:
+			c.imports["encoding/json"] = true
+			buf.WriteString("switch y := x.(type) {\n")
+			buf.WriteString("case json.Number:\nif i, err := y.Int64(); err == nil {\nx, owned, matched = i, false, true\n}\n")
+			buf.WriteString("case int64:\nmatched = true\n}\n")
+			if integral {
+				c.imports["math"] = true
+				buf.WriteString("if !matched {\n")
+				buf.WriteString("if f, ok, err := jsonschema.CompiledFloat(x); ok && err == nil && f == math.Trunc(f) {\nmatched = true\n}\n")
+				buf.WriteString("}\n")
+			}
+
+		case NullType:
+			buf.WriteString("if x == nil {\nmatched = true\n}\n")
+
+		case NumberType:
+			c.imports["encoding/json"] = true
+			buf.WriteString("switch y := x.(type) {\n")
+			buf.WriteString("case json.Number:\nif f, err := y.Float64(); err == nil {\nx, owned, matched = f, false, true\n}\n")
+			buf.WriteString("case float64:\nmatched = true\n")
+			buf.WriteString("case int64:\nx, owned, matched = float64(y), false, true\n}\n")
+
+		case ObjectType:
+			buf.WriteString("if _, ok := x.(map[string]interface{}); ok {\nmatched = true\n}\n")
+
+		case StringType:
+			buf.WriteString("if _, ok := x.(string); ok {\nmatched = true\n}\n")
+		}
+
+		if i > 0 {
+			buf.WriteString("}\n")
+		}
+	}
+
+	buf.WriteString("if !matched {\n")
+	buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrInvalidType{Types: []jsonschema.PrimitiveType{%s}}", strings.Join(types, ", "))))
+	buf.WriteString("}\n}\n")
+}
+
+// compileNumber reports err for the numbers (in f) which match the
+// condition cond.
+func (c *GoCompiler) compileNumber(buf *bytes.Buffer, k *compiledKeyword, cond, err string) {
+	if strings.Contains(cond, "math.") {
+		c.imports["math"] = true
+	}
+	buf.WriteString("if f, ok, err := jsonschema.CompiledFloat(x); ok {\n")
+	buf.WriteString("if err != nil {\nerrs = append(errs, err)\n")
+	fmt.Fprintf(buf, "} else if %s {\n", cond)
+	buf.WriteString(k.report(err))
+	buf.WriteString("}\n}\n")
+}
+
+func (c *GoCompiler) compileFormat(buf *bytes.Buffer, k *compiledKeyword, v *formatValidator) error {
+	uri, found := rootEnvFormat(v.name, v.format)
+	if !found {
+		return fmt.Errorf("format %q is not a format of RootEnv", v.name)
+	}
+
+	name := c.newVar("format", fmt.Sprintf("jsonschema.CompiledFormat(%q, %q)", uri, v.name))
+	fmt.Fprintf(buf, "if !%s.IsValid(x) {\n", name)
+	buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrInvalidFormat{Format: %q}", v.name)))
+	buf.WriteString("}\n")
+	return nil
+}
+
+// rootEnvFormat returns the URI of a dialect of RootEnv which has the
+// format validator v for the format name.
+func rootEnvFormat(name string, v FormatValidator) (string, bool) {
+	uris := make([]string, 0, len(RootEnv.dialects))
+	for uri := range RootEnv.dialects {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	for _, uri := range uris {
+		f := RootEnv.dialects[uri].formats[name]
+		if f != nil && reflect.TypeOf(f) == reflect.TypeOf(v) {
+			return uri, true
+		}
+	}
+	return "", false
+}
+
+func (c *GoCompiler) compileItems(buf *bytes.Buffer, k *compiledKeyword, v *itemsValidator) error {
+	item := func(schema *Schema, index string) error {
+		f, err := c.subschema(k.schema, schema, false)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "if z, _, err := %s(y[%s], false, %s); err != nil {\n", f, index, k.child(schema, "index: "+index))
+		buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrInvalidItem{Index: %s, Err: err}", index)))
+		buf.WriteString("} else {\n")
+		fmt.Fprintf(buf, "x, owned = jsonschema.CompiledSetItem(x, owned, %s, z)\n", index)
+		buf.WriteString("}\n")
+		return nil
+	}
+
+	switch {
+	case v.item != nil:
+		buf.WriteString("if y, ok := x.([]interface{}); ok && y != nil {\n")
+		buf.WriteString("for i := range y {\n")
+		err := item(v.item, "i")
+		if err != nil {
+			return err
+		}
+		buf.WriteString("}\n}\n")
+
+	case v.items != nil:
+		buf.WriteString("if y, ok := x.([]interface{}); ok && y != nil {\n")
+		for i, schema := range v.items {
+			fmt.Fprintf(buf, "if len(y) > %d {\n", i)
+			err := item(schema, strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+			buf.WriteString("}\n")
+		}
+		if v.additionalItem != nil {
+			fmt.Fprintf(buf, "for i := %d; i < len(y); i++ {\n", len(v.items))
+			err := item(v.additionalItem, "i")
+			if err != nil {
+				return err
+			}
+			buf.WriteString("}\n")
+		}
+		buf.WriteString("}\n")
+	}
+
+	return nil
+}
+
+func (c *GoCompiler) compileProperties(buf *bytes.Buffer, k *compiledKeyword, v *propertiesValidator) error {
+	if len(v.properties) == 0 && len(v.patterns) == 0 && v.additionalProperties == nil {
+		return nil
+	}
+
+	property := func(schema *Schema) error {
+		f, err := c.subschema(k.schema, schema, false)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "if z, _, err := %s(m, false, %s); err != nil {\n", f, k.child(schema, "property: k, index: -1"))
+		buf.WriteString(k.report("&jsonschema.ErrInvalidProperty{Property: k, Err: err}"))
+		buf.WriteString("} else {\nm = z\n")
+		buf.WriteString("x, owned = jsonschema.CompiledSetProperty(x, owned, k, z)\n")
+		buf.WriteString("}\n")
+		return nil
+	}
+
+	buf.WriteString("if y, ok := x.(map[string]interface{}); ok && y != nil {\n")
+	buf.WriteString("for k, m := range y {\n")
+
+	additional := v.additionalProperties != nil
+	if additional {
+		buf.WriteString("additional := true\n")
+	}
+
+	if len(v.properties) > 0 {
+		names := make([]string, 0, len(v.properties))
+		for name := range v.properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		buf.WriteString("switch k {\n")
+		for _, name := range names {
+			fmt.Fprintf(buf, "case %q:\n", name)
+			if additional {
+				buf.WriteString("additional = false\n")
+			}
+			err := property(v.properties[name])
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}\n")
+	}
+
+	for _, pattern := range v.patterns {
+		fmt.Fprintf(buf, "if %s.MatchString(k) {\n", c.regexp(pattern.pattern))
+		if additional {
+			buf.WriteString("additional = false\n")
+		}
+		err := property(pattern.schema)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("}\n")
+	}
+
+	if additional {
+		buf.WriteString("if additional {\n")
+		err := property(v.additionalProperties)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("}\n")
+	}
+
+	buf.WriteString("}\n}\n")
+	return nil
+}
+
+func (c *GoCompiler) compileDependencies(buf *bytes.Buffer, k *compiledKeyword, v *dependenciesValidator) error {
+	names := make([]string, 0, len(v.dependencies))
+	for name := range v.dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf.WriteString("if y, ok := x.(map[string]interface{}); ok && y != nil {\n")
+	for _, name := range names {
+		fmt.Fprintf(buf, "if _, found := y[%q]; found {\n", name)
+
+		switch d := v.dependencies[name].(type) {
+		case []string:
+			for _, dep := range d {
+				fmt.Fprintf(buf, "if _, found := y[%q]; !found {\n", dep)
+				buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrInvalidDependency{Property: %q, Dependency: %q}", name, dep)))
+				buf.WriteString("}\n")
+			}
+
+		case *Schema:
+			f, err := c.subschema(k.schema, d, false)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(buf, "if _, _, err := %s(x, false, %s); err != nil {\n", f, k.child(d, ""))
+			buf.WriteString(k.report(fmt.Sprintf("&jsonschema.ErrInvalidDependency{Property: %q, Subschema: %s, Err: err}", name, c.schemaVar(d))))
+			buf.WriteString("}\n")
+		}
+
+		buf.WriteString("}\n")
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+func (c *GoCompiler) regexp(pattern string) string {
+	c.imports["regexp"] = true
+	return c.newVar("pattern", fmt.Sprintf("regexp.MustCompile(%q)", pattern))
+}
+
+func (c *GoCompiler) schemaList(schemas []*Schema) string {
+	names := make([]string, len(schemas))
+	for i, schema := range schemas {
+		names[i] = c.schemaVar(schema)
+	}
+	return fmt.Sprintf("[]*jsonschema.Schema{%s}", strings.Join(names, ", "))
+}
+
+// literal returns a Go expression for the JSON value x (with the same Go
+// types as x).
+func (c *GoCompiler) literal(x interface{}) (string, error) {
+	switch y := x.(type) {
+	case nil:
+		return "nil", nil
+	case bool:
+		return strconv.FormatBool(y), nil
+	case string:
+		return strconv.Quote(y), nil
+	case json.Number:
+		c.imports["encoding/json"] = true
+		return fmt.Sprintf("json.Number(%q)", y), nil
+	case int64:
+		return fmt.Sprintf("int64(%d)", y), nil
+	case float64:
+		return fmt.Sprintf("float64(%s)", formatFloat(y)), nil
+
+	case []interface{}:
+		items := make([]string, len(y))
+		for i, item := range y {
+			s, err := c.literal(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return fmt.Sprintf("[]interface{}{%s}", strings.Join(items, ", ")), nil
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(y))
+		for k := range y {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		members := make([]string, len(keys))
+		for i, k := range keys {
+			s, err := c.literal(y[k])
+			if err != nil {
+				return "", err
+			}
+			members[i] = fmt.Sprintf("%q: %s", k, s)
+		}
+		return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(members, ", ")), nil
+
+	default:
+		return "", fmt.Errorf("unsupported value: %#v", x)
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// compiledRuntime is added to each generated file.
+const compiledRuntime = `
+// location is the location of a value in the instance and of the schema
+// it is validated with. The locations are only formatted for the errors.
+type location struct {
+	parent   *location
+	property string
+	index    int
+	hasToken bool
+
+	// keyword is the location of the schema relative to the schema of the
+	// parent.
+	keyword string
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func (l *location) instanceLocation() string {
+	if l == nil {
+		return ""
+	}
+	s := l.parent.instanceLocation()
+	switch {
+	case !l.hasToken:
+		return s
+	case l.index >= 0:
+		return s + "/" + strconv.Itoa(l.index)
+	default:
+		return s + "/" + pointerEscaper.Replace(l.property)
+	}
+}
+
+func (l *location) keywordLocation() string {
+	if l == nil {
+		return ""
+	}
+	return l.parent.keywordLocation() + l.keyword
+}
+
+func report(errs []error, err error, keyword string, x interface{}, schema *jsonschema.Schema, loc *location) []error {
+	keywordLocation := loc.keywordLocation()
+	if keyword != "" {
+		keywordLocation += "/" + pointerEscaper.Replace(keyword)
+	}
+	return append(errs, jsonschema.CompiledReport(err, keyword, x, schema, loc.instanceLocation(), keywordLocation))
+}
+`
+
+const compiledParseURL = `
+func mustParseURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+`
+
+// The Compiled functions below are used by the code generated by
+// GoCompiler. They are not part of the stable API of the package: they
+// change with the generated code (so the generated code must be
+// regenerated when the package is updated) and they should not be called
+// by other code.
+
+// CompiledReport fills in the details of err (like the Context does for the
+// errors reported by the validators) and returns it.
+func CompiledReport(err error, keyword string, value interface{}, schema *Schema, instanceLocation, keywordLocation string) error {
+	if e, ok := err.(reportable); ok {
+		e.report(errorKeyword(schema, err, keyword), value, schema, nil, instanceLocation, keywordLocation)
+	}
+	return err
+}
+
+// CompiledValue converts the Go value x to the values decoded by
+// encoding/json like Schema.Validate does. Objects and arrays are expected
+// to be decoded by encoding/json already and are returned as is (without
+// walking their members).
+func CompiledValue(x interface{}) interface{} {
+	switch x.(type) {
+	case map[string]interface{}, []interface{}:
+		return x
+	}
+	return adaptValue(x)
+}
+
+// CompiledEqual compares JSON values like `enum` and `const` do.
+func CompiledEqual(a, b interface{}) (bool, error) {
+	return isEqual(a, b)
+}
+
+// CompiledFloat returns the value of the JSON number x (ok is false when x
+// is not a number).
+func CompiledFloat(x interface{}) (f float64, ok bool, err error) {
+	return toFloat(x)
+}
+
+// CompiledUniqueItems returns the errors of `uniqueItems` for the array x.
+func CompiledUniqueItems(x []interface{}) []error {
+	return uniqueItemsErrors(x)
+}
+
+// CompiledFormat returns the format name of the dialect uri of RootEnv. It
+// panics when there is no such format.
+func CompiledFormat(uri, name string) FormatValidator {
+	if d, found := RootEnv.dialects[normalizeRef(uri)]; found {
+		if f := d.formats[name]; f != nil {
+			return f
+		}
+	}
+	panic(fmt.Sprintf("jsonschema: unknown format %q of dialect %s", name, uri))
+}
+
+// CompiledSetProperty sets the property k of the object x to v (unless it
+// is already v). x is copied first unless it is owned (a copy made by an
+// earlier update). It returns the object and whether it is owned.
+func CompiledSetProperty(x interface{}, owned bool, k string, v interface{}) (interface{}, bool) {
+	y, ok := x.(map[string]interface{})
+	if !ok {
+		return x, owned
+	}
+	if m, found := y[k]; found && sameValue(m, v) {
+		return x, owned
+	}
+
+	if !owned {
+		z := make(map[string]interface{}, len(y))
+		for k, m := range y {
+			z[k] = m
+		}
+		y = z
+	}
+	y[k] = v
+	return y, true
+}
+
+// CompiledSetItem sets the item i of the array x to v like
+// CompiledSetProperty.
+func CompiledSetItem(x interface{}, owned bool, i int, v interface{}) (interface{}, bool) {
+	y, ok := x.([]interface{})
+	if !ok || i >= len(y) || sameValue(y[i], v) {
+		return x, owned
+	}
+
+	if !owned {
+		z := make([]interface{}, len(y))
+		copy(z, y)
+		y = z
+	}
+	y[i] = v
+	return y, true
+}
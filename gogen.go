@@ -0,0 +1,959 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoGenerator generates Go types from schemas.
+//
+// Objects become structs with `json` tags, `enum` becomes a named type with
+// a constant for each value and `oneOf`/`anyOf` become a struct with a field
+// for each variant which (un)marshals the variant which is set. The
+// `definitions` (and `$defs`) of the added schemas become named types.
+//
+// `x-go-type` (like "time.Duration" or "github.com/google/uuid.UUID")
+// replaces the type of a schema by an existing type and `x-go-name` sets
+// the name of the type of a definition or of the field of a property.
+type GoGenerator struct {
+	// Package is the name of the package of the generated file.
+	Package string
+
+	names   map[*Schema]string
+	roots   map[*Schema]bool
+	taken   map[string]bool
+	queue   []*Schema
+	decls   map[string]string
+	imports map[string]bool
+}
+
+// NewGoGenerator returns a generator for the package pkg.
+func NewGoGenerator(pkg string) *GoGenerator {
+	return &GoGenerator{
+		Package: pkg,
+		names:   map[*Schema]string{},
+		roots:   map[*Schema]bool{},
+		taken:   map[string]bool{},
+		decls:   map[string]string{},
+		imports: map[string]bool{},
+	}
+}
+
+// AddType adds a type named name (converted to a Go identifier) for schema
+// and for each of its definitions.
+func (g *GoGenerator) AddType(name string, schema *Schema) {
+	if _, found := schema.Definition["x-go-type"]; !found {
+		g.register(schema, goName(name))
+	}
+	g.roots[rootSchema(schema)] = true
+	g.addDefinitions(schema)
+}
+
+// addDocument adds the types of the document of a referenced schema. The
+// root schema is named after the last segment of its URI.
+func (g *GoGenerator) addDocument(schema *Schema) {
+	root := rootSchema(schema)
+	if g.roots[root] {
+		return
+	}
+	g.roots[root] = true
+
+	if root.Id != nil {
+		name := path.Base(root.Id.Path)
+		name = strings.TrimSuffix(name, path.Ext(name))
+		if k := g.kind(root); name != "" && name != "/" && name != "." && (k == goStruct || k == goEnum || k == goUnion) {
+			if _, found := root.Definition["x-go-type"]; !found {
+				g.register(root, goName(name))
+			}
+		}
+	}
+	g.addDefinitions(root)
+}
+
+func rootSchema(schema *Schema) *Schema {
+	for schema.parent != nil {
+		schema = schema.parent
+	}
+	return schema
+}
+
+func (g *GoGenerator) addDefinitions(schema *Schema) {
+	for _, keyword := range []string{"definitions", "$defs"} {
+		defs, _ := schema.Definition[keyword].(map[string]interface{})
+
+		keys := make([]string, 0, len(defs))
+		for k := range defs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			def := schema.subschema("/" + escapeJSONPointer(keyword) + "/" + escapeJSONPointer(k))
+			if def == nil {
+				continue
+			}
+			if _, found := def.Definition["x-go-type"]; !found {
+				g.register(def, goName(k))
+			}
+			g.addDefinitions(def)
+		}
+	}
+}
+
+// Generate returns the formatted source of the types.
+func (g *GoGenerator) Generate() ([]byte, error) {
+	for len(g.queue) > 0 {
+		schema := g.queue[0]
+		g.queue = g.queue[1:]
+
+		err := g.declare(schema)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", g.names[schema], err)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by jsonschema-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", g.Package)
+
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			imports = append(imports, path)
+		}
+		sort.Strings(imports)
+
+		buf.WriteString("import (\n")
+		for _, path := range imports {
+			fmt.Fprintf(&buf, "\t%q\n", path)
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	names := make([]string, 0, len(g.decls))
+	for name := range g.decls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		buf.WriteString(g.decls[name])
+		buf.WriteString("\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %s\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// register gives schema a (unique) type name and queues its declaration.
+func (g *GoGenerator) register(schema *Schema, name string) string {
+	if name, found := g.names[schema]; found {
+		return name
+	}
+
+	if x, ok := schema.Definition["x-go-name"].(string); ok && x != "" {
+		name = x
+	}
+
+	base := name
+	for i := 2; g.taken[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	g.taken[name] = true
+	g.names[schema] = name
+	g.queue = append(g.queue, schema)
+	return name
+}
+
+// goTypeKind is the kind of Go type generated for a schema.
+type goTypeKind int
+
+const (
+	goOther goTypeKind = iota
+	goStruct
+	goEnum
+	goUnion
+	goAlias
+)
+
+// structuralKeywords are the keywords which make a schema with a `$ref`
+// (2019-09 and later) more than a reference.
+var structuralKeywords = []string{
+	"type", "properties", "additionalProperties", "items", "prefixItems",
+	"allOf", "anyOf", "oneOf", "enum", "const",
+}
+
+// step returns the schema referenced by schema (or nil when it is not a
+// reference).
+func (g *GoGenerator) step(schema *Schema) *Schema {
+	if schema.RefSchema != nil {
+		return schema.RefSchema
+	}
+
+	if _, found := schema.Definition["$ref"]; !found {
+		return nil
+	}
+	for _, k := range structuralKeywords {
+		if _, found := schema.Definition[k]; found {
+			return nil
+		}
+	}
+	for _, validator := range schema.Validators {
+		if v, ok := validator.(*refValidator); ok && v.schema != nil {
+			if v.schema.RefSchema != nil {
+				return v.schema.RefSchema
+			}
+			return v.schema
+		}
+	}
+	return nil
+}
+
+// resolve follows the references of schema.
+func (g *GoGenerator) resolve(schema *Schema) *Schema {
+	for i := 0; i < 32; i++ {
+		next := g.step(schema)
+		if next == nil {
+			break
+		}
+		schema = next
+	}
+	return schema
+}
+
+func (g *GoGenerator) kind(schema *Schema) goTypeKind {
+	if g.step(schema) != nil {
+		return goAlias
+	}
+
+	def := schema.Definition
+	if _, found := def["x-go-type"]; found {
+		return goOther
+	}
+	if _, found := def["enum"]; found {
+		return goEnum
+	}
+	if _, found := def["oneOf"]; found {
+		return goUnion
+	}
+	if _, found := def["anyOf"]; found {
+		return goUnion
+	}
+	if _, found := def["properties"]; found {
+		return goStruct
+	}
+	if _, found := def["allOf"]; found {
+		return goStruct
+	}
+	return goOther
+}
+
+// lookupName returns the type name of schema (or of one of the schemas it
+// refers to).
+func (g *GoGenerator) lookupName(schema *Schema) (string, bool) {
+	for i := 0; schema != nil && i < 32; i++ {
+		if name, found := g.names[schema]; found {
+			return name, true
+		}
+		schema = g.step(schema)
+	}
+	return "", false
+}
+
+// expr returns the Go type of schema. Schemas which need a declaration are
+// named after hint.
+func (g *GoGenerator) expr(schema *Schema, hint string) (string, error) {
+	for s, i := schema, 0; s != nil && i < 32; s, i = g.step(s), i+1 {
+		g.addDocument(s)
+	}
+
+	if name, found := g.lookupName(schema); found {
+		return name, nil
+	}
+
+	schema = g.resolve(schema)
+	switch g.kind(schema) {
+	case goStruct, goEnum, goUnion:
+		return g.register(schema, hint), nil
+	default:
+		return g.underlying(schema, hint)
+	}
+}
+
+// underlying returns the Go type of a schema which doesn't need a
+// declaration.
+func (g *GoGenerator) underlying(schema *Schema, hint string) (string, error) {
+	if schema.Bool != nil {
+		return "interface{}", nil
+	}
+
+	def := schema.Definition
+	if x, found := def["x-go-type"]; found {
+		s, ok := x.(string)
+		if !ok || s == "" {
+			return "", fmt.Errorf("invalid 'x-go-type': %#v", x)
+		}
+		return g.externalType(s), nil
+	}
+
+	types := schemaTypes(schema)
+	if len(types) != 1 {
+		return "interface{}", nil
+	}
+
+	format, _ := def["format"].(string)
+
+	switch types[0] {
+	case "string":
+		switch {
+		case format == "date-time":
+			g.imports["time"] = true
+			return "time.Time", nil
+		case format == "byte" || def["contentEncoding"] == "base64":
+			return "[]byte", nil
+		default:
+			return "string", nil
+		}
+
+	case "integer":
+		switch format {
+		case "int32":
+			return "int32", nil
+		default:
+			return "int64", nil
+		}
+
+	case "number":
+		switch format {
+		case "float":
+			return "float32", nil
+		default:
+			return "float64", nil
+		}
+
+	case "boolean":
+		return "bool", nil
+
+	case "array":
+		items := schema.subschema("/items")
+		if items == nil || items.Definition == nil && items.RefSchema == nil && items.Bool == nil {
+			return "[]interface{}", nil
+		}
+		if _, isTuple := def["items"].([]interface{}); isTuple {
+			return "[]interface{}", nil
+		}
+		t, err := g.expr(items, hint+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "[]" + t, nil
+
+	case "object":
+		values := schema.subschema("/additionalProperties")
+		if values == nil || values.Bool != nil {
+			return "map[string]interface{}", nil
+		}
+		t, err := g.expr(values, hint+"Value")
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + t, nil
+
+	default:
+		return "interface{}", nil
+	}
+}
+
+// externalType returns the Go type for an `x-go-type` (like "*time.Time" or
+// "[]github.com/google/uuid.UUID") and adds its import.
+func (g *GoGenerator) externalType(s string) string {
+	prefix := ""
+	for {
+		switch {
+		case strings.HasPrefix(s, "*"):
+			prefix += "*"
+			s = s[1:]
+			continue
+		case strings.HasPrefix(s, "[]"):
+			prefix += "[]"
+			s = s[2:]
+			continue
+		}
+		break
+	}
+
+	dot := strings.LastIndex(s, ".")
+	if dot < 0 || strings.LastIndex(s, "/") > dot {
+		return prefix + s
+	}
+
+	path, name := s[:dot], s[dot+1:]
+	g.imports[path] = true
+
+	pkg := path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		pkg = path[i+1:]
+		if majorVersionRegexp.MatchString(pkg) && i > 0 {
+			if j := strings.LastIndex(path[:i], "/"); j >= 0 {
+				pkg = path[j+1 : i]
+			} else {
+				pkg = path[:i]
+			}
+		}
+	}
+	pkg = strings.ReplaceAll(pkg, "-", "")
+	return prefix + pkg + "." + name
+}
+
+var majorVersionRegexp = regexp.MustCompile(`^v[0-9]+$`)
+
+// declare generates the declaration of the named type of schema.
+func (g *GoGenerator) declare(schema *Schema) error {
+	var (
+		name = g.names[schema]
+		buf  bytes.Buffer
+	)
+
+	writeComment(&buf, schema)
+
+	switch g.kind(schema) {
+	case goAlias:
+		t, err := g.expr(g.step(schema), name+"Target")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "type %s = %s\n", name, t)
+
+	case goStruct:
+		err := g.declareStruct(&buf, schema, name)
+		if err != nil {
+			return err
+		}
+
+	case goEnum:
+		err := g.declareEnum(&buf, schema, name)
+		if err != nil {
+			return err
+		}
+
+	case goUnion:
+		err := g.declareUnion(&buf, schema, name)
+		if err != nil {
+			return err
+		}
+
+	default:
+		t, err := g.underlying(schema, name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "type %s %s\n", name, t)
+	}
+
+	g.decls[name] = buf.String()
+	return nil
+}
+
+func (g *GoGenerator) declareStruct(buf *bytes.Buffer, schema *Schema, name string) error {
+	var (
+		embedded []string
+		fields   bytes.Buffer
+		taken    = map[string]bool{}
+		required = map[string]bool{}
+	)
+
+	// the named structs in `allOf` are embedded; the properties of the
+	// other parts are merged
+	parts := []*Schema{schema}
+	if allOf, ok := schema.Definition["allOf"].([]interface{}); ok {
+		for i := range allOf {
+			part := schema.subschema(fmt.Sprintf("/allOf/%d", i))
+			if part == nil {
+				continue
+			}
+
+			resolved := g.resolve(part)
+			if partName, isNamed := g.lookupName(part); isNamed && g.kind(resolved) == goStruct {
+				embedded = append(embedded, partName)
+				continue
+			}
+			parts = append(parts, resolved)
+		}
+	}
+
+	for _, part := range parts {
+		if r, ok := part.Definition["required"].([]interface{}); ok {
+			for _, k := range r {
+				if s, ok := k.(string); ok {
+					required[s] = true
+				}
+			}
+		}
+	}
+
+	for _, part := range parts {
+		properties, _ := part.Definition["properties"].(map[string]interface{})
+
+		keys := make([]string, 0, len(properties))
+		for k := range properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			property := part.subschema("/properties/" + escapeJSONPointer(k))
+			if property == nil {
+				continue
+			}
+
+			fieldName := goName(k)
+			if x, ok := property.Definition["x-go-name"].(string); ok && x != "" {
+				fieldName = x
+			}
+			for base, i := fieldName, 2; taken[fieldName]; i++ {
+				fieldName = base + strconv.Itoa(i)
+			}
+			taken[fieldName] = true
+
+			t, err := g.expr(property, name+fieldName)
+			if err != nil {
+				return err
+			}
+
+			nullable := isNullable(g.resolve(property)) || t == name
+			if (!required[k] || nullable) && !strings.HasPrefix(t, "[]") && !strings.HasPrefix(t, "map[") && t != "interface{}" && !strings.HasPrefix(t, "*") {
+				t = "*" + t
+			}
+
+			tag := k
+			if !required[k] {
+				tag += ",omitempty"
+			}
+
+			writeFieldComment(&fields, property)
+			fmt.Fprintf(&fields, "\t%s %s `json:%q`\n", fieldName, t, tag)
+		}
+	}
+
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, t := range embedded {
+		fmt.Fprintf(buf, "\t%s\n", t)
+	}
+	buf.Write(fields.Bytes())
+	buf.WriteString("}\n")
+	return nil
+}
+
+func (g *GoGenerator) declareEnum(buf *bytes.Buffer, schema *Schema, name string) error {
+	values, ok := schema.Definition["enum"].([]interface{})
+	if !ok {
+		return fmt.Errorf("invalid 'enum': %#v", schema.Definition["enum"])
+	}
+
+	var (
+		base   = "interface{}"
+		consts bytes.Buffer
+		taken  = map[string]bool{}
+	)
+
+	strs, ints := true, true
+	for _, v := range values {
+		switch y := v.(type) {
+		case string:
+			ints = false
+		case json.Number:
+			strs = false
+			if _, err := y.Int64(); err != nil {
+				ints = false
+			}
+		default:
+			strs, ints = false, false
+		}
+	}
+
+	switch {
+	case strs && len(values) > 0:
+		base = "string"
+	case ints && len(values) > 0:
+		base = "int64"
+	default:
+		t, err := g.underlying(schema, name)
+		if err != nil {
+			return err
+		}
+		base = t
+	}
+
+	fmt.Fprintf(buf, "type %s %s\n", name, base)
+	if !strs && !ints {
+		return nil
+	}
+
+	for _, v := range values {
+		var (
+			constName string
+			literal   string
+		)
+		switch y := v.(type) {
+		case string:
+			constName = name + goName(y)
+			if y == "" {
+				constName = name + "Empty"
+			}
+			literal = strconv.Quote(y)
+		case json.Number:
+			constName = name + strings.Replace(y.String(), "-", "Minus", 1)
+			literal = y.String()
+		}
+
+		for base, i := constName, 2; taken[constName] || g.taken[constName]; i++ {
+			constName = base + strconv.Itoa(i)
+		}
+		taken[constName] = true
+
+		fmt.Fprintf(&consts, "\t%s %s = %s\n", constName, name, literal)
+	}
+
+	fmt.Fprintf(buf, "\nconst (\n%s)\n", consts.Bytes())
+	return nil
+}
+
+func (g *GoGenerator) declareUnion(buf *bytes.Buffer, schema *Schema, name string) error {
+	keyword := "oneOf"
+	if _, found := schema.Definition[keyword]; !found {
+		keyword = "anyOf"
+	}
+	variants, _ := schema.Definition[keyword].([]interface{})
+
+	type variant struct {
+		field    string
+		typ      string
+		name     string
+		required []string
+	}
+
+	var (
+		fields []variant
+		taken  = map[string]bool{}
+	)
+
+	for i := range variants {
+		sub := schema.subschema(fmt.Sprintf("/%s/%d", keyword, i))
+		if sub == nil {
+			continue
+		}
+
+		t, err := g.expr(sub, fmt.Sprintf("%sVariant%d", name, i+1))
+		if err != nil {
+			return err
+		}
+
+		field := variantFieldName(t)
+		for base, j := field, 2; taken[field]; j++ {
+			field = base + strconv.Itoa(j)
+		}
+		taken[field] = true
+
+		variantName, _ := g.lookupName(sub)
+		fields = append(fields, variant{field: field, typ: t, name: variantName, required: g.requiredProperties(sub)})
+	}
+
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, f := range fields {
+		fmt.Fprintf(buf, "\t%s *%s\n", f.field, f.typ)
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(buf, "func (u %s) MarshalJSON() ([]byte, error) {\n\tswitch {\n", name)
+	for _, f := range fields {
+		fmt.Fprintf(buf, "\tcase u.%s != nil:\n\t\treturn json.Marshal(u.%s)\n", f.field, f.field)
+	}
+	buf.WriteString("\t}\n\treturn []byte(\"null\"), nil\n}\n\n")
+
+	fmt.Fprintf(buf, "func (u *%s) UnmarshalJSON(data []byte) error {\n\t*u = %s{}\n", name, name)
+
+	g.imports["encoding/json"] = true
+	g.imports["fmt"] = true
+
+	discriminator, _ := schema.Definition["discriminator"].(map[string]interface{})
+	if property, ok := discriminator["propertyName"].(string); ok && property != "" {
+		mapping := map[string]string{}
+		if m, ok := discriminator["mapping"].(map[string]interface{}); ok {
+			for value, ref := range m {
+				if s, ok := ref.(string); ok {
+					mapping[value] = goName(s[strings.LastIndex(s, "/")+1:])
+				}
+			}
+		}
+
+		fmt.Fprintf(buf, "\tvar probe struct {\n\t\tValue string `json:%q`\n\t}\n", property)
+		buf.WriteString("\tif err := json.Unmarshal(data, &probe); err != nil {\n\t\treturn err\n\t}\n")
+		buf.WriteString("\tswitch probe.Value {\n")
+		for _, f := range fields {
+			var values []string
+			for value, target := range mapping {
+				if target == f.name {
+					values = append(values, value)
+				}
+			}
+			if len(mapping) == 0 && f.name != "" {
+				values = append(values, f.name)
+			}
+			if len(values) == 0 {
+				continue
+			}
+			sort.Strings(values)
+			for i, value := range values {
+				values[i] = strconv.Quote(value)
+			}
+			fmt.Fprintf(buf, "\tcase %s:\n\t\tu.%s = new(%s)\n\t\treturn json.Unmarshal(data, u.%s)\n", strings.Join(values, ", "), f.field, f.typ, f.field)
+		}
+		fmt.Fprintf(buf, "\t}\n\treturn fmt.Errorf(\"%s: unknown %s %%q\", probe.Value)\n}\n", name, property)
+		return nil
+	}
+
+	// without a discriminator the first variant which can be decoded
+	// (without unknown fields and with its required properties) is used
+	g.imports["bytes"] = true
+	for _, f := range fields {
+		if len(f.required) > 0 {
+			buf.WriteString("\tvar members map[string]json.RawMessage\n")
+			buf.WriteString("\tjson.Unmarshal(data, &members) // members is nil when data is not an object\n")
+			break
+		}
+	}
+	for _, f := range fields {
+		cond := "err == nil"
+		for _, k := range f.required {
+			cond += fmt.Sprintf(" && members[%q] != nil", k)
+		}
+		fmt.Fprintf(buf, "\t{\n\t\tvar v %s\n", f.typ)
+		buf.WriteString("\t\tdec := json.NewDecoder(bytes.NewReader(data))\n\t\tdec.DisallowUnknownFields()\n")
+		fmt.Fprintf(buf, "\t\tif err := dec.Decode(&v); %s {\n\t\t\tu.%s = &v\n\t\t\treturn nil\n\t\t}\n\t}\n", cond, f.field)
+	}
+	fmt.Fprintf(buf, "\treturn fmt.Errorf(\"%s: value does not match any of the variants\")\n}\n", name)
+	return nil
+}
+
+// requiredProperties returns the sorted `required` properties of schema
+// (and of the parts of its `allOf`) following the references.
+func (g *GoGenerator) requiredProperties(schema *Schema) []string {
+	var (
+		required []string
+		seen     = map[string]bool{}
+		visit    func(schema *Schema, depth int)
+	)
+
+	visit = func(schema *Schema, depth int) {
+		if depth > 32 {
+			return
+		}
+		schema = g.resolve(schema)
+
+		if r, ok := schema.Definition["required"].([]interface{}); ok {
+			for _, k := range r {
+				if s, ok := k.(string); ok && !seen[s] {
+					seen[s] = true
+					required = append(required, s)
+				}
+			}
+		}
+		if allOf, ok := schema.Definition["allOf"].([]interface{}); ok {
+			for i := range allOf {
+				if part := schema.subschema(fmt.Sprintf("/allOf/%d", i)); part != nil {
+					visit(part, depth+1)
+				}
+			}
+		}
+	}
+	visit(schema, 0)
+
+	sort.Strings(required)
+	return required
+}
+
+// variantFieldName returns the name of the field of a union for a variant
+// of type t.
+func variantFieldName(t string) string {
+	switch {
+	case strings.HasPrefix(t, "[]"):
+		return variantFieldName(t[2:]) + "List"
+	case strings.HasPrefix(t, "map["):
+		return "Map"
+	case strings.HasPrefix(t, "*"):
+		return variantFieldName(t[1:])
+	}
+
+	if i := strings.LastIndex(t, "."); i >= 0 {
+		t = t[i+1:]
+	}
+
+	switch t {
+	case "interface{}":
+		return "Value"
+	case "string":
+		return "String"
+	case "bool":
+		return "Bool"
+	case "int32", "int64":
+		return "Int"
+	case "float32", "float64":
+		return "Number"
+	default:
+		return t
+	}
+}
+
+// subschema returns the schema defined at pointer in s.
+func (s *Schema) subschema(pointer string) *Schema {
+	if child, found := s.children[pointer]; found {
+		return child
+	}
+
+	// the schemas in unknown keywords are built for each level (like
+	// `/definitions` and `/Pet` in dialects without `definitions`)
+	for i := 1; i < len(pointer); i++ {
+		if pointer[i] != '/' {
+			continue
+		}
+		if child, found := s.children[pointer[:i]]; found {
+			if sub := child.subschema(pointer[i:]); sub != nil {
+				return sub
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypes returns the types in the `type` of schema (without "null").
+func schemaTypes(schema *Schema) []string {
+	var types []string
+	switch t := schema.Definition["type"].(type) {
+	case string:
+		types = append(types, t)
+	case []interface{}:
+		for _, x := range t {
+			if s, ok := x.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	result := types[:0]
+	for _, t := range types {
+		if t != "null" {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// isNullable returns true when the `type` of schema includes "null" (or
+// when it has `"nullable": true`).
+func isNullable(schema *Schema) bool {
+	if schema.Definition["nullable"] == true {
+		return true
+	}
+	if t, ok := schema.Definition["type"].([]interface{}); ok {
+		for _, x := range t {
+			if x == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeComment(buf *bytes.Buffer, schema *Schema) {
+	for _, k := range []string{"title", "description"} {
+		if s, ok := schema.Definition[k].(string); ok && s != "" {
+			for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+				fmt.Fprintf(buf, "// %s\n", strings.TrimSpace(line))
+			}
+			return
+		}
+	}
+}
+
+func writeFieldComment(buf *bytes.Buffer, schema *Schema) {
+	var comment bytes.Buffer
+	writeComment(&comment, schema)
+	for _, line := range strings.SplitAfter(comment.String(), "\n") {
+		if line != "" {
+			buf.WriteString("\t" + line)
+		}
+	}
+}
+
+// goInitialisms are the words which are written in upper case in Go names.
+var goInitialisms = map[string]bool{
+	"acl": true, "api": true, "ascii": true, "cpu": true, "css": true,
+	"dns": true, "eof": true, "guid": true, "html": true, "http": true,
+	"https": true, "id": true, "ip": true, "json": true, "rpc": true,
+	"sla": true, "smtp": true, "sql": true, "ssh": true, "tcp": true,
+	"tls": true, "ttl": true, "udp": true, "ui": true, "uid": true,
+	"uri": true, "url": true, "utf8": true, "uuid": true, "xml": true,
+}
+
+// goName converts a JSON name (like "created_at" or "petId") to an exported
+// Go identifier (like "CreatedAt" or "PetID").
+func goName(s string) string {
+	var (
+		words []string
+		word  []rune
+		prev  rune
+	)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+
+	var name strings.Builder
+	for _, w := range words {
+		if goInitialisms[strings.ToLower(w)] {
+			name.WriteString(strings.ToUpper(w))
+			continue
+		}
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+
+	result := name.String()
+	if result == "" {
+		return "Value"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		return "X" + result
+	}
+	return result
+}
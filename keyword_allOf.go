@@ -17,12 +17,11 @@ func (v *allOfValidator) Setup(builder Builder) error {
 
 		schemas := make([]*Schema, len(y))
 		for i, a := range y {
-			b, ok := a.(map[string]interface{})
-			if !ok {
+			if !isSchema(a) {
 				return fmt.Errorf("invalid 'allOf' definition: %#v", x)
 			}
 
-			schema, err := builder.Build(fmt.Sprintf("/allOf/%d", i), b)
+			schema, err := builder.Build(fmt.Sprintf("/allOf/%d", i), a)
 			if err != nil {
 				return err
 			}
@@ -42,7 +41,7 @@ func (v *allOfValidator) Validate(x interface{}, ctx *Context) {
 	)
 
 	for i, schema := range v.schemas {
-		_, err := ctx.ValidateSelfWith(schema)
+		_, err := ctx.validateSelfWith(schema, true)
 
 		if err != nil {
 			failed = true
@@ -56,6 +55,6 @@ func (v *allOfValidator) Validate(x interface{}, ctx *Context) {
 	}
 
 	if failed {
-		ctx.Report(&ErrNotAllOf{x, ctx.CurrentSchema(), v.schemas, errors})
+		ctx.Report(&ErrNotAllOf{Subschemas: v.schemas, Errors: errors})
 	}
 }
@@ -0,0 +1,13 @@
+package jsonschema
+
+// annotationValidator is used for keywords which only annotate an instance
+// (like `readOnly` or `$comment`) and never cause validation to fail.
+type annotationValidator struct {
+}
+
+func (v *annotationValidator) Setup(builder Builder) error {
+	return nil
+}
+
+func (v *annotationValidator) Validate(x interface{}, ctx *Context) {
+}
@@ -17,12 +17,11 @@ func (v *anyOfValidator) Setup(builder Builder) error {
 
 		schemas := make([]*Schema, len(y))
 		for i, a := range y {
-			b, ok := a.(map[string]interface{})
-			if !ok {
+			if !isSchema(a) {
 				return fmt.Errorf("invalid 'anyOf' definition: %#v", x)
 			}
 
-			schema, err := builder.Build(fmt.Sprintf("/anyOf/%d", i), b)
+			schema, err := builder.Build(fmt.Sprintf("/anyOf/%d", i), a)
 			if err != nil {
 				return err
 			}
@@ -38,12 +37,19 @@ func (v *anyOfValidator) Setup(builder Builder) error {
 func (v *anyOfValidator) Validate(x interface{}, ctx *Context) {
 	var (
 		errors []error
+		passed bool
 	)
 
 	for i, schema := range v.schemas {
 		_, err := ctx.ValidateSelfWith(schema)
 		if err == nil {
-			return
+			// keep going when the evaluated properties and items of all
+			// the valid subschemas are needed.
+			if !ctx.tracking() {
+				return
+			}
+			passed = true
+			continue
 		}
 
 		if errors == nil {
@@ -53,5 +59,7 @@ func (v *anyOfValidator) Validate(x interface{}, ctx *Context) {
 		errors[i] = err
 	}
 
-	ctx.Report(&ErrNotAnyOf{x, v.schemas, errors})
+	if !passed {
+		ctx.Report(&ErrNotAnyOf{Subschemas: v.schemas, Errors: errors})
+	}
 }
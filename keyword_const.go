@@ -0,0 +1,24 @@
+package jsonschema
+
+type constValidator struct {
+	value interface{}
+}
+
+func (v *constValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("const"); found {
+		v.value = x
+	}
+	return nil
+}
+
+func (v *constValidator) Validate(x interface{}, ctx *Context) {
+	equal, err := isEqual(x, v.value)
+	if err != nil {
+		ctx.Report(err)
+		return
+	}
+
+	if !equal {
+		ctx.Report(&ErrNotConst{Const: v.value})
+	}
+}
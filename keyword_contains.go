@@ -0,0 +1,106 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type containsValidator struct {
+	schema *Schema
+	min    int
+	max    int
+
+	// evaluates is set when the matching items count as evaluated (for
+	// `unevaluatedItems`).
+	evaluates bool
+}
+
+// boundedContainsValidator implements the `contains` keyword together with
+// the `minContains` and `maxContains` keywords of draft 2019-09.
+type boundedContainsValidator struct {
+	containsValidator
+}
+
+// evaluatingContainsValidator implements the `contains` keyword of draft
+// 2020-12 whose matching items are evaluated (unlike in draft 2019-09).
+type evaluatingContainsValidator struct {
+	boundedContainsValidator
+}
+
+func (v *containsValidator) Setup(builder Builder) error {
+	v.min = 1
+	v.max = -1
+
+	if x, found := builder.GetKeyword("contains"); found {
+		if !isSchema(x) {
+			return fmt.Errorf("invalid 'contains' definition: %#v", x)
+		}
+
+		schema, err := builder.Build("/contains", x)
+		if err != nil {
+			return err
+		}
+
+		v.schema = schema
+	}
+	return nil
+}
+
+func (v *boundedContainsValidator) Setup(builder Builder) error {
+	err := v.containsValidator.Setup(builder)
+	if err != nil {
+		return err
+	}
+
+	if x, found := builder.GetKeyword("minContains"); found {
+		i, ok, err := toInteger(x)
+		if !ok || err != nil || i < 0 {
+			return fmt.Errorf("invalid 'minContains' definition: %#v", x)
+		}
+		v.min = int(i)
+	}
+
+	if x, found := builder.GetKeyword("maxContains"); found {
+		i, ok, err := toInteger(x)
+		if !ok || err != nil || i < 0 {
+			return fmt.Errorf("invalid 'maxContains' definition: %#v", x)
+		}
+		v.max = int(i)
+	}
+
+	return nil
+}
+
+func (v *evaluatingContainsValidator) Setup(builder Builder) error {
+	v.evaluates = true
+	return v.boundedContainsValidator.Setup(builder)
+}
+
+func (v *containsValidator) Validate(x interface{}, ctx *Context) {
+	y, ok := x.([]interface{})
+	if !ok || y == nil || v.schema == nil {
+		return
+	}
+
+	var (
+		count    int
+		complete = v.max < 0 && !(v.evaluates && ctx.tracking())
+	)
+
+	for i, item := range y {
+		err := ctx.checkItemWith(i, item, v.schema)
+		if err == nil {
+			count++
+			if v.evaluates {
+				ctx.markItemEvaluated(i)
+			}
+
+			if complete && count >= v.min {
+				return
+			}
+		}
+	}
+
+	if count < v.min || (v.max >= 0 && count > v.max) {
+		ctx.Report(&ErrNotContains{Subschema: v.schema, Count: count, Min: v.min, Max: v.max})
+	}
+}
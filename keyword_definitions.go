@@ -7,21 +7,34 @@ import (
 type definitionsValidator struct {
 }
 
+// defsValidator implements the `$defs` keyword which replaced `definitions`
+// in draft 2019-09.
+type defsValidator struct {
+	definitionsValidator
+}
+
 func (v *definitionsValidator) Setup(builder Builder) error {
-	if x, found := builder.GetKeyword("definitions"); found {
+	return v.setup(builder, "definitions")
+}
+
+func (v *defsValidator) Setup(builder Builder) error {
+	return v.setup(builder, "$defs")
+}
+
+func (v *definitionsValidator) setup(builder Builder, keyword string) error {
+	if x, found := builder.GetKeyword(keyword); found {
 		y, ok := x.(map[string]interface{})
 		if !ok || y == nil {
-			return fmt.Errorf("invalid 'definitions' definition: %#v", x)
+			return fmt.Errorf("invalid '%s' definition: %#v", keyword, x)
 		}
 
 		schemas := make(map[string]*Schema, len(y))
 		for name, a := range y {
-			b, ok := a.(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("invalid 'definitions' definition: %#v", x)
+			if !isSchema(a) {
+				return fmt.Errorf("invalid '%s' definition: %#v", keyword, x)
 			}
 
-			schema, err := builder.Build("/definitions/"+escapeJSONPointer(name), b)
+			schema, err := builder.Build("/"+escapeJSONPointer(keyword)+"/"+escapeJSONPointer(name), a)
 			if err != nil {
 				return err
 			}
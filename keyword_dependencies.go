@@ -18,6 +18,10 @@ func (v *dependenciesValidator) Setup(builder Builder) error {
 		dependencies := make(map[string]interface{}, len(y))
 		for dependant, a := range y {
 			switch b := a.(type) {
+			case string:
+				// draft-03 allows a single dependency
+				dependencies[dependant] = []string{b}
+
 			case []interface{}:
 				deps := make([]string, len(b))
 				for i, d := range b {
@@ -29,7 +33,7 @@ func (v *dependenciesValidator) Setup(builder Builder) error {
 				}
 				dependencies[dependant] = deps
 
-			case map[string]interface{}:
+			case map[string]interface{}, bool:
 				schema, err := builder.Build("/dependencies/"+escapeJSONPointer(dependant), b)
 				if err != nil {
 					return err
@@ -68,7 +72,7 @@ func (v *dependenciesValidator) Validate(x interface{}, ctx *Context) {
 		case *Schema:
 			_, err := ctx.ValidateValueWith(x, d)
 			if err != nil {
-				ctx.Report(&ErrInvalidDependency{Property: k, Schema: d, Err: err})
+				ctx.Report(&ErrInvalidDependency{Property: k, Subschema: d, Err: err})
 			}
 
 		}
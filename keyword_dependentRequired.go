@@ -0,0 +1,58 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type dependentRequiredValidator struct {
+	dependencies map[string][]string
+}
+
+func (v *dependentRequiredValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("dependentRequired"); found {
+		y, ok := x.(map[string]interface{})
+		if !ok || y == nil {
+			return fmt.Errorf("invalid 'dependentRequired' definition: %#v", x)
+		}
+
+		dependencies := make(map[string][]string, len(y))
+		for dependant, a := range y {
+			b, ok := a.([]interface{})
+			if !ok {
+				return fmt.Errorf("invalid 'dependentRequired' definition: %#v", x)
+			}
+
+			deps := make([]string, len(b))
+			for i, d := range b {
+				if e, ok := d.(string); ok {
+					deps[i] = e
+				} else {
+					return fmt.Errorf("invalid 'dependentRequired' definition: %#v", x)
+				}
+			}
+			dependencies[dependant] = deps
+		}
+
+		v.dependencies = dependencies
+	}
+	return nil
+}
+
+func (v *dependentRequiredValidator) Validate(x interface{}, ctx *Context) {
+	y, ok := x.(map[string]interface{})
+	if !ok || y == nil {
+		return
+	}
+
+	for k, deps := range v.dependencies {
+		if _, found := y[k]; !found {
+			continue
+		}
+
+		for _, dep := range deps {
+			if _, found := y[dep]; !found {
+				ctx.Report(&ErrInvalidDependency{Property: k, Dependency: dep})
+			}
+		}
+	}
+}
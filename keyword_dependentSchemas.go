@@ -0,0 +1,52 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type dependentSchemasValidator struct {
+	dependencies map[string]*Schema
+}
+
+func (v *dependentSchemasValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("dependentSchemas"); found {
+		y, ok := x.(map[string]interface{})
+		if !ok || y == nil {
+			return fmt.Errorf("invalid 'dependentSchemas' definition: %#v", x)
+		}
+
+		dependencies := make(map[string]*Schema, len(y))
+		for dependant, a := range y {
+			if !isSchema(a) {
+				return fmt.Errorf("invalid 'dependentSchemas' definition: %#v", x)
+			}
+
+			schema, err := builder.Build("/dependentSchemas/"+escapeJSONPointer(dependant), a)
+			if err != nil {
+				return err
+			}
+			dependencies[dependant] = schema
+		}
+
+		v.dependencies = dependencies
+	}
+	return nil
+}
+
+func (v *dependentSchemasValidator) Validate(x interface{}, ctx *Context) {
+	y, ok := x.(map[string]interface{})
+	if !ok || y == nil {
+		return
+	}
+
+	for k, schema := range v.dependencies {
+		if _, found := y[k]; !found {
+			continue
+		}
+
+		_, err := ctx.validateSelfWith(schema, true)
+		if err != nil {
+			ctx.Report(&ErrInvalidDependency{Property: k, Subschema: schema, Err: err})
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package jsonschema
+
+// disallowValidator implements the draft-03 `disallow` keyword which rejects
+// the values matching any of the types in its union.
+type disallowValidator struct {
+	unionTypeValidator
+}
+
+func (v *disallowValidator) Setup(builder Builder) error {
+	return v.setup(builder, "disallow")
+}
+
+func (v *disallowValidator) Validate(x interface{}, ctx *Context) {
+	if v.matches(x, ctx) {
+		ctx.Report(&ErrDisallowedType{})
+	}
+}
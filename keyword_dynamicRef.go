@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// dynamicRefValidator implements the `$dynamicRef` keyword. When the
+// statically resolved target defines a matching `$dynamicAnchor`, the
+// outermost schema resource in the dynamic scope which defines the same
+// anchor is used instead.
+type dynamicRefValidator struct {
+	schema *Schema
+	anchor string
+}
+
+func (v *dynamicRefValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("$dynamicRef"); found {
+		y, ok := x.(string)
+		if !ok {
+			return fmt.Errorf("invalid '$dynamicRef' definition: %#v", x)
+		}
+
+		u, err := url.Parse(y)
+		if err != nil {
+			return fmt.Errorf("invalid '$dynamicRef' definition: %#v (%s)", x, err)
+		}
+
+		schema, err := builder.Reference(y)
+		if err != nil {
+			return err
+		}
+
+		v.schema = schema
+		if u.Fragment != "" && !strings.HasPrefix(u.Fragment, "/") {
+			v.anchor = u.Fragment
+		}
+	}
+	return nil
+}
+
+func (v *dynamicRefValidator) Validate(x interface{}, ctx *Context) {
+	_, err := ctx.validateSelfWith(resolveDynamicRef(ctx, v.schema, v.anchor, v.anchor != ""), true)
+	if err != nil {
+		ctx.Report(err)
+	}
+}
+
+// resolveDynamicRef returns the schema in the dynamic scope which should be
+// used instead of the statically resolved reference.
+func resolveDynamicRef(ctx *Context, ref *Schema, anchor string, dynamic bool) *Schema {
+	target := ref.RefSchema
+	if !dynamic || target == nil || target.resource == nil {
+		return ref
+	}
+
+	if target.resource.dynamicAnchors[anchor] != target {
+		return ref
+	}
+
+	if s := ctx.dynamicScope(anchor); s != nil {
+		return s
+	}
+
+	return ref
+}
@@ -31,5 +31,5 @@ func (v *enumValidator) Validate(x interface{}, ctx *Context) {
 		}
 	}
 
-	ctx.Report(&ErrInvalidEnum{v.enum, x})
+	ctx.Report(&ErrInvalidEnum{Enum: v.enum})
 }
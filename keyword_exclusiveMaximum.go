@@ -0,0 +1,39 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type exclusiveMaximumValidator struct {
+	max float64
+}
+
+func (v *exclusiveMaximumValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("exclusiveMaximum"); found {
+		f, ok, err := toFloat(x)
+		if !ok {
+			return fmt.Errorf("invalid 'exclusiveMaximum' definition: %#v", x)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid 'exclusiveMaximum' definition: %#v (%s)", x, err)
+		}
+
+		v.max = f
+	}
+	return nil
+}
+
+func (v *exclusiveMaximumValidator) Validate(x interface{}, ctx *Context) {
+	f, ok, err := toFloat(x)
+	if !ok {
+		return
+	}
+	if err != nil {
+		ctx.Report(err)
+		return
+	}
+
+	if f >= v.max {
+		ctx.Report(&ErrTooLarge{Max: v.max, Exclusive: true})
+	}
+}
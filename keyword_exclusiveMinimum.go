@@ -0,0 +1,39 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type exclusiveMinimumValidator struct {
+	min float64
+}
+
+func (v *exclusiveMinimumValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("exclusiveMinimum"); found {
+		f, ok, err := toFloat(x)
+		if !ok {
+			return fmt.Errorf("invalid 'exclusiveMinimum' definition: %#v", x)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid 'exclusiveMinimum' definition: %#v (%s)", x, err)
+		}
+
+		v.min = f
+	}
+	return nil
+}
+
+func (v *exclusiveMinimumValidator) Validate(x interface{}, ctx *Context) {
+	f, ok, err := toFloat(x)
+	if !ok {
+		return
+	}
+	if err != nil {
+		ctx.Report(err)
+		return
+	}
+
+	if f <= v.min {
+		ctx.Report(&ErrTooSmall{Min: v.min, Exclusive: true})
+	}
+}
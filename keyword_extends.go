@@ -0,0 +1,46 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+// extendsValidator implements the draft-03 `extends` keyword. The value must
+// be valid against the extended schema (or all of the extended schemas).
+type extendsValidator struct {
+	allOfValidator
+}
+
+func (v *extendsValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("extends"); found {
+		switch y := x.(type) {
+
+		case map[string]interface{}:
+			schema, err := builder.Build("/extends", y)
+			if err != nil {
+				return err
+			}
+			v.schemas = []*Schema{schema}
+
+		case []interface{}:
+			schemas := make([]*Schema, len(y))
+			for i, a := range y {
+				if !isSchema(a) {
+					return fmt.Errorf("invalid 'extends' definition: %#v", x)
+				}
+
+				schema, err := builder.Build(fmt.Sprintf("/extends/%d", i), a)
+				if err != nil {
+					return err
+				}
+
+				schemas[i] = schema
+			}
+			v.schemas = schemas
+
+		default:
+			return fmt.Errorf("invalid 'extends' definition: %#v", x)
+
+		}
+	}
+	return nil
+}
@@ -9,6 +9,19 @@ type formatValidator struct {
 	format FormatValidator
 }
 
+// openFormatValidator implements the OpenAPI `format` keyword which may
+// name any format. Unknown formats are ignored.
+type openFormatValidator struct {
+	formatValidator
+}
+
+// annotatingFormatValidator implements the `format` keyword of drafts
+// 2019-09 and 2020-12 which is only an annotation unless the validation is
+// run WithFormatAssertion. Unknown formats are always ignored.
+type annotatingFormatValidator struct {
+	openFormatValidator
+}
+
 func (v *formatValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("format"); found {
 		y, ok := x.(string)
@@ -27,8 +40,33 @@ func (v *formatValidator) Setup(builder Builder) error {
 	return nil
 }
 
+func (v *openFormatValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("format"); found {
+		y, ok := x.(string)
+		if !ok {
+			return fmt.Errorf("invalid 'format' definition: %#v", x)
+		}
+
+		v.name = y
+		v.format = builder.GetFormatValidator(y)
+	}
+	return nil
+}
+
+func (v *openFormatValidator) Validate(x interface{}, ctx *Context) {
+	if v.format != nil {
+		v.formatValidator.Validate(x, ctx)
+	}
+}
+
 func (v *formatValidator) Validate(x interface{}, ctx *Context) {
 	if !v.format.IsValid(x) {
-		ctx.Report(&ErrInvalidFormat{x, v.name})
+		ctx.Report(&ErrInvalidFormat{Format: v.name})
+	}
+}
+
+func (v *annotatingFormatValidator) Validate(x interface{}, ctx *Context) {
+	if ctx.assertFormat {
+		v.openFormatValidator.Validate(x, ctx)
 	}
 }
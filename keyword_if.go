@@ -0,0 +1,62 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type ifValidator struct {
+	ifSchema   *Schema
+	thenSchema *Schema
+	elseSchema *Schema
+}
+
+func (v *ifValidator) Setup(builder Builder) error {
+	for _, keyword := range []string{"if", "then", "else"} {
+		x, found := builder.GetKeyword(keyword)
+		if !found {
+			continue
+		}
+
+		if !isSchema(x) {
+			return fmt.Errorf("invalid '%s' definition: %#v", keyword, x)
+		}
+
+		schema, err := builder.Build("/"+keyword, x)
+		if err != nil {
+			return err
+		}
+
+		switch keyword {
+		case "if":
+			v.ifSchema = schema
+		case "then":
+			v.thenSchema = schema
+		case "else":
+			v.elseSchema = schema
+		}
+	}
+	return nil
+}
+
+func (v *ifValidator) Validate(x interface{}, ctx *Context) {
+	if v.ifSchema == nil {
+		return
+	}
+
+	_, err := ctx.ValidateSelfWith(v.ifSchema)
+	if err == nil {
+		if v.thenSchema != nil {
+			_, err := ctx.validateSelfWith(v.thenSchema, true)
+			if err != nil {
+				ctx.Report(&ErrNotThen{Subschema: v.thenSchema, Err: err})
+			}
+		}
+	} else {
+		if v.elseSchema != nil {
+			_, err := ctx.validateSelfWith(v.elseSchema, true)
+			if err != nil {
+				ctx.Report(&ErrNotElse{Subschema: v.elseSchema, Err: err})
+			}
+		}
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"fmt"
 )
 
-var additionalItemsDenied = &Schema{}
-
 type itemsValidator struct {
 	item           *Schema
 	items          []*Schema
@@ -16,7 +14,7 @@ func (v *itemsValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("items"); found {
 		switch y := x.(type) {
 
-		case map[string]interface{}:
+		case map[string]interface{}, bool:
 			s, err := builder.Build("/items", y)
 			if err != nil {
 				return err
@@ -26,11 +24,10 @@ func (v *itemsValidator) Setup(builder Builder) error {
 		case []interface{}:
 			l := make([]*Schema, len(y))
 			for i, a := range y {
-				b, ok := a.(map[string]interface{})
-				if !ok {
+				if !isSchema(a) {
 					return fmt.Errorf("invalid 'items' definition: %#v", y)
 				}
-				s, err := builder.Build(fmt.Sprintf("/items/%d", i), b)
+				s, err := builder.Build(fmt.Sprintf("/items/%d", i), a)
 				if err != nil {
 					return err
 				}
@@ -45,24 +42,15 @@ func (v *itemsValidator) Setup(builder Builder) error {
 	}
 
 	if x, ok := builder.GetKeyword("additionalItems"); ok {
-		switch y := x.(type) {
-
-		case map[string]interface{}:
-			s, err := builder.Build("/additionalItems", y)
-			if err != nil {
-				return err
-			}
-			v.additionalItem = s
-
-		case bool:
-			if !y {
-				v.additionalItem = additionalItemsDenied
-			}
-
-		default:
-			return fmt.Errorf("invalid 'additionalItems' definition: %#v", y)
+		if !isSchema(x) {
+			return fmt.Errorf("invalid 'additionalItems' definition: %#v", x)
+		}
 
+		s, err := builder.Build("/additionalItems", x)
+		if err != nil {
+			return err
 		}
+		v.additionalItem = s
 	}
 
 	return nil
@@ -76,20 +64,21 @@ func (v *itemsValidator) Validate(x interface{}, ctx *Context) {
 
 	if v.item != nil {
 		for i, l := 0, len(y); i < l; i++ {
-			newValue, err := ctx.ValidateValueWith(y[i], v.item)
+			newValue, err := ctx.ValidateItemWith(i, y[i], v.item)
 			if err != nil {
-				ctx.Report(&ErrInvalidItem{i, err})
+				ctx.Report(&ErrInvalidItem{Index: i, Err: err})
 			} else {
-				y[i] = newValue
+				ctx.setItem(i, newValue)
 			}
 		}
+		ctx.markItemsEvaluated(len(y))
 
 		// no additionalItems
 
 		return
 	}
 
-	if len(v.items) > 0 {
+	if v.items != nil {
 		var (
 			i  = 0
 			la = len(y)
@@ -97,26 +86,27 @@ func (v *itemsValidator) Validate(x interface{}, ctx *Context) {
 		)
 
 		for ; i < la && i < lb; i++ {
-			newValue, err := ctx.ValidateValueWith(y[i], v.items[i])
+			newValue, err := ctx.ValidateItemWith(i, y[i], v.items[i])
 			if err != nil {
-				ctx.Report(&ErrInvalidItem{i, err})
+				ctx.Report(&ErrInvalidItem{Index: i, Err: err})
 			} else {
-				y[i] = newValue
+				ctx.setItem(i, newValue)
 			}
 		}
 
+		ctx.markItemsEvaluated(i)
+
 		// additionalItems
-		if v.additionalItem == additionalItemsDenied {
-			for ; i < la; i++ {
-				ctx.Report(&ErrInvalidItem{i, fmt.Errorf("additional item is not allowed")})
-			}
-		} else if v.additionalItem != nil {
+		if v.additionalItem != nil {
+			ctx.markItemsEvaluated(la)
+		}
+		if v.additionalItem != nil {
 			for ; i < la; i++ {
-				newValue, err := ctx.ValidateValueWith(y[i], v.additionalItem)
+				newValue, err := ctx.ValidateItemWith(i, y[i], v.additionalItem)
 				if err != nil {
-					ctx.Report(&ErrInvalidItem{i, err})
+					ctx.Report(&ErrInvalidItem{Index: i, Err: err})
 				} else {
-					y[i] = newValue
+					ctx.setItem(i, newValue)
 				}
 			}
 		}
@@ -10,10 +10,13 @@ type maxItemsValidator struct {
 
 func (v *maxItemsValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("maxItems"); found {
-		i, ok := x.(int64)
+		i, ok, err := toInteger(x)
 		if !ok {
 			return fmt.Errorf("invalid 'maxItems' definition: %#v", x)
 		}
+		if err != nil {
+			return fmt.Errorf("invalid 'maxItems' definition: %#v (%s)", x, err)
+		}
 
 		v.max = int(i)
 	}
@@ -27,6 +30,6 @@ func (v *maxItemsValidator) Validate(x interface{}, ctx *Context) {
 	}
 
 	if len(y) > v.max {
-		ctx.Report(&ErrTooLong{v.max, x})
+		ctx.Report(&ErrTooLong{Max: v.max})
 	}
 }
@@ -11,10 +11,13 @@ type maxLengthValidator struct {
 
 func (v *maxLengthValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("maxLength"); found {
-		i, ok := x.(int64)
+		i, ok, err := toInteger(x)
 		if !ok {
 			return fmt.Errorf("invalid 'maxLength' definition: %#v", x)
 		}
+		if err != nil {
+			return fmt.Errorf("invalid 'maxLength' definition: %#v (%s)", x, err)
+		}
 
 		v.max = int(i)
 	}
@@ -30,6 +33,6 @@ func (v *maxLengthValidator) Validate(x interface{}, ctx *Context) {
 	l := utf8.RuneCountInString(y)
 
 	if l > v.max {
-		ctx.Report(&ErrTooLong{v.max, x})
+		ctx.Report(&ErrTooLong{Max: v.max})
 	}
 }
@@ -10,10 +10,13 @@ type maxPropertiesValidator struct {
 
 func (v *maxPropertiesValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("maxProperties"); found {
-		i, ok := x.(int64)
+		i, ok, err := toInteger(x)
 		if !ok {
 			return fmt.Errorf("invalid 'maxProperties' definition: %#v", x)
 		}
+		if err != nil {
+			return fmt.Errorf("invalid 'maxProperties' definition: %#v (%s)", x, err)
+		}
 
 		v.max = int(i)
 	}
@@ -29,6 +32,6 @@ func (v *maxPropertiesValidator) Validate(x interface{}, ctx *Context) {
 	l := len(y)
 
 	if l > v.max {
-		ctx.Report(&ErrTooLong{v.max, x})
+		ctx.Report(&ErrTooLong{Max: v.max})
 	}
 }
@@ -11,13 +11,13 @@ type maximumValidator struct {
 
 func (v *maximumValidator) Setup(builder Builder) error {
 	if x, ok := builder.GetKeyword("exclusiveMaximum"); ok {
-		y, ok := x.(bool)
-
-		if !ok {
+		// a numeric exclusiveMaximum (draft-06 and later) is a keyword on its
+		// own and is handled by the exclusiveMaximumValidator.
+		if y, ok := x.(bool); ok {
+			v.exclusive = y
+		} else if _, ok, _ := toFloat(x); !ok {
 			return fmt.Errorf("invalid 'exclusiveMaximum' definition: %#v", x)
 		}
-
-		v.exclusive = y
 	}
 
 	if x, found := builder.GetKeyword("maximum"); found {
@@ -52,6 +52,6 @@ func (v *maximumValidator) Validate(x interface{}, ctx *Context) {
 	}
 
 	if !ok {
-		ctx.Report(&ErrTooLarge{v.max, v.exclusive, x})
+		ctx.Report(&ErrTooLarge{Max: v.max, Exclusive: v.exclusive})
 	}
 }
@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"encoding/json"
 	"fmt"
 )
 
@@ -11,12 +10,10 @@ type minItemsValidator struct {
 
 func (v *minItemsValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("minItems"); found {
-		y, ok := x.(json.Number)
+		i, ok, err := toInteger(x)
 		if !ok {
 			return fmt.Errorf("invalid 'minItems' definition: %#v", x)
 		}
-
-		i, err := y.Int64()
 		if err != nil {
 			return fmt.Errorf("invalid 'minItems' definition: %#v (%s)", x, err)
 		}
@@ -33,6 +30,6 @@ func (v *minItemsValidator) Validate(x interface{}, ctx *Context) {
 	}
 
 	if len(y) < v.min {
-		ctx.Report(&ErrTooShort{v.min, x})
+		ctx.Report(&ErrTooShort{Min: v.min})
 	}
 }
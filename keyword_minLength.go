@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"encoding/json"
 	"fmt"
 	"unicode/utf8"
 )
@@ -12,12 +11,10 @@ type minLengthValidator struct {
 
 func (v *minLengthValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("minLength"); found {
-		y, ok := x.(json.Number)
+		i, ok, err := toInteger(x)
 		if !ok {
 			return fmt.Errorf("invalid 'minLength' definition: %#v", x)
 		}
-
-		i, err := y.Int64()
 		if err != nil {
 			return fmt.Errorf("invalid 'minLength' definition: %#v (%s)", x, err)
 		}
@@ -36,6 +33,6 @@ func (v *minLengthValidator) Validate(x interface{}, ctx *Context) {
 	l := utf8.RuneCountInString(y)
 
 	if l < v.min {
-		ctx.Report(&ErrTooShort{v.min, x})
+		ctx.Report(&ErrTooShort{Min: v.min})
 	}
 }
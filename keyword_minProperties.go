@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"encoding/json"
 	"fmt"
 )
 
@@ -11,12 +10,10 @@ type minPropertiesValidator struct {
 
 func (v *minPropertiesValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("minProperties"); found {
-		y, ok := x.(json.Number)
+		i, ok, err := toInteger(x)
 		if !ok {
 			return fmt.Errorf("invalid 'minProperties' definition: %#v", x)
 		}
-
-		i, err := y.Int64()
 		if err != nil {
 			return fmt.Errorf("invalid 'minProperties' definition: %#v (%s)", x, err)
 		}
@@ -35,6 +32,6 @@ func (v *minPropertiesValidator) Validate(x interface{}, ctx *Context) {
 	l := len(y)
 
 	if l < v.min {
-		ctx.Report(&ErrTooLong{v.min, x})
+		ctx.Report(&ErrTooShort{Min: v.min})
 	}
 }
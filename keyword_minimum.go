@@ -11,13 +11,13 @@ type minimumValidator struct {
 
 func (v *minimumValidator) Setup(builder Builder) error {
 	if x, ok := builder.GetKeyword("exclusiveMinimum"); ok {
-		y, ok := x.(bool)
-
-		if !ok {
+		// a numeric exclusiveMinimum (draft-06 and later) is a keyword on its
+		// own and is handled by the exclusiveMinimumValidator.
+		if y, ok := x.(bool); ok {
+			v.exclusive = y
+		} else if _, ok, _ := toFloat(x); !ok {
 			return fmt.Errorf("invalid 'exclusiveMinimum' definition: %#v", x)
 		}
-
-		v.exclusive = y
 	}
 
 	if x, found := builder.GetKeyword("minimum"); found {
@@ -51,6 +51,6 @@ func (v *minimumValidator) Validate(x interface{}, ctx *Context) {
 	}
 
 	if !ok {
-		ctx.Report(&ErrTooSmall{v.min, v.exclusive, x})
+		ctx.Report(&ErrTooSmall{Min: v.min, Exclusive: v.exclusive})
 	}
 }
@@ -9,15 +9,32 @@ type multipleOfValidator struct {
 	factor float64
 }
 
+// divisibleByValidator implements the draft-03 `divisibleBy` keyword which
+// was renamed to `multipleOf` in draft-04.
+type divisibleByValidator struct {
+	multipleOfValidator
+}
+
 func (v *multipleOfValidator) Setup(builder Builder) error {
-	if x, found := builder.GetKeyword("multipleOf"); found {
-		f, ok := x.(float64)
+	return v.setup(builder, "multipleOf")
+}
+
+func (v *divisibleByValidator) Setup(builder Builder) error {
+	return v.setup(builder, "divisibleBy")
+}
+
+func (v *multipleOfValidator) setup(builder Builder, keyword string) error {
+	if x, found := builder.GetKeyword(keyword); found {
+		f, ok, err := toFloat(x)
 		if !ok {
-			return fmt.Errorf("invalid 'multipleOf' definition: %#v", x)
+			return fmt.Errorf("invalid '%s' definition: %#v", keyword, x)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid '%s' definition: %#v (%s)", keyword, x, err)
 		}
 
 		if f < math.SmallestNonzeroFloat64 {
-			return fmt.Errorf("invalid 'multipleOf' definition: %#v", x)
+			return fmt.Errorf("invalid '%s' definition: %#v", keyword, x)
 		}
 
 		v.factor = f
@@ -40,6 +57,6 @@ func (v *multipleOfValidator) Validate(x interface{}, ctx *Context) {
 	ok = rem < 0.000000001
 
 	if !ok {
-		ctx.Report(&ErrNotMultipleOf{v.factor, x})
+		ctx.Report(&ErrNotMultipleOf{Factor: v.factor})
 	}
 }
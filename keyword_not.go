@@ -10,12 +10,11 @@ type notValidator struct {
 
 func (v *notValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("not"); found {
-		y, ok := x.(map[string]interface{})
-		if !ok || y == nil {
+		if !isSchema(x) {
 			return fmt.Errorf("invalid 'not' definition: %#v", x)
 		}
 
-		schema, err := builder.Build("/not", y)
+		schema, err := builder.Build("/not", x)
 		if err != nil {
 			return err
 		}
@@ -28,6 +27,6 @@ func (v *notValidator) Setup(builder Builder) error {
 func (v *notValidator) Validate(x interface{}, ctx *Context) {
 	_, err := ctx.ValidateSelfWith(v.schema)
 	if err == nil {
-		ctx.Report(&ErrNotNot{x, v.schema})
+		ctx.Report(&ErrNotNot{Subschema: v.schema})
 	}
 }
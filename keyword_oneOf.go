@@ -17,12 +17,11 @@ func (v *oneOfValidator) Setup(builder Builder) error {
 
 		schemas := make([]*Schema, len(y))
 		for i, a := range y {
-			b, ok := a.(map[string]interface{})
-			if !ok {
+			if !isSchema(a) {
 				return fmt.Errorf("invalid 'oneOf' definition: %#v", x)
 			}
 
-			schema, err := builder.Build(fmt.Sprintf("/oneOf/%d", i), b)
+			schema, err := builder.Build(fmt.Sprintf("/oneOf/%d", i), a)
 			if err != nil {
 				return err
 			}
@@ -56,6 +55,6 @@ func (v *oneOfValidator) Validate(x interface{}, ctx *Context) {
 	}
 
 	if passed != 1 {
-		ctx.Report(&ErrNotOneOf{x, v.schemas, errors})
+		ctx.Report(&ErrNotOneOf{Subschemas: v.schemas, Errors: errors})
 	}
 }
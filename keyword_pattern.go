@@ -35,6 +35,6 @@ func (v *patternValidator) Validate(x interface{}, ctx *Context) {
 	}
 
 	if !v.regexp.MatchString(y) {
-		ctx.Report(&ErrInvalidPattern{v.pattern, y})
+		ctx.Report(&ErrInvalidPattern{Pattern: v.pattern})
 	}
 }
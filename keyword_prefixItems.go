@@ -0,0 +1,53 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+// prefixItemsValidator implements the `prefixItems` and `items` keywords of
+// draft 2020-12 where `items` only applies to the items after those matched
+// by `prefixItems`.
+type prefixItemsValidator struct {
+	itemsValidator
+}
+
+func (v *prefixItemsValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("prefixItems"); found {
+		y, ok := x.([]interface{})
+		if !ok || len(y) == 0 {
+			return fmt.Errorf("invalid 'prefixItems' definition: %#v", x)
+		}
+
+		l := make([]*Schema, len(y))
+		for i, a := range y {
+			if !isSchema(a) {
+				return fmt.Errorf("invalid 'prefixItems' definition: %#v", y)
+			}
+			s, err := builder.Build(fmt.Sprintf("/prefixItems/%d", i), a)
+			if err != nil {
+				return err
+			}
+			l[i] = s
+		}
+		v.items = l
+	}
+
+	if x, found := builder.GetKeyword("items"); found {
+		if !isSchema(x) {
+			return fmt.Errorf("invalid 'items' definition: %#v", x)
+		}
+
+		s, err := builder.Build("/items", x)
+		if err != nil {
+			return err
+		}
+
+		if len(v.items) > 0 {
+			v.additionalItem = s
+		} else {
+			v.item = s
+		}
+	}
+
+	return nil
+}
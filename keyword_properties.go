@@ -3,6 +3,7 @@ package jsonschema
 import (
 	"fmt"
 	"regexp"
+	"sort"
 )
 
 type propertiesValidator struct {
@@ -11,14 +12,19 @@ type propertiesValidator struct {
 	additionalProperties *Schema
 }
 
+// requiredPropertiesValidator implements the draft-03 `properties` keyword
+// where a property is made required with `"required": true` in its schema.
+type requiredPropertiesValidator struct {
+	propertiesValidator
+	required []string
+}
+
 type patternProperty struct {
 	pattern string
 	regexp  *regexp.Regexp
 	schema  *Schema
 }
 
-var additionalPropertiesDenied = &Schema{}
-
 func (v *propertiesValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("properties"); found {
 		defs, ok := x.(map[string]interface{})
@@ -28,12 +34,11 @@ func (v *propertiesValidator) Setup(builder Builder) error {
 
 		properties := make(map[string]*Schema, len(defs))
 		for k, y := range defs {
-			mdef, ok := y.(map[string]interface{})
-			if !ok {
+			if !isSchema(y) {
 				return fmt.Errorf("invalid 'properties' definition: %#v", x)
 			}
 
-			schema, err := builder.Build("/properties/"+escapeJSONPointer(k), mdef)
+			schema, err := builder.Build("/properties/"+escapeJSONPointer(k), y)
 			if err != nil {
 				return err
 			}
@@ -51,8 +56,7 @@ func (v *propertiesValidator) Setup(builder Builder) error {
 
 		patterns := make([]*patternProperty, 0, len(defs))
 		for k, y := range defs {
-			mdef, ok := y.(map[string]interface{})
-			if !ok {
+			if !isSchema(y) {
 				return fmt.Errorf("invalid 'patternProperties' definition: %#v", x)
 			}
 
@@ -61,7 +65,7 @@ func (v *propertiesValidator) Setup(builder Builder) error {
 				return fmt.Errorf("invalid 'patternProperties' definition: %#v (%s)", x, err)
 			}
 
-			schema, err := builder.Build("/patternProperties/"+escapeJSONPointer(k), mdef)
+			schema, err := builder.Build("/patternProperties/"+escapeJSONPointer(k), y)
 			if err != nil {
 				return err
 			}
@@ -69,28 +73,23 @@ func (v *propertiesValidator) Setup(builder Builder) error {
 			patterns = append(patterns, &patternProperty{k, reg, schema})
 		}
 
+		// apply the patterns in a stable order (a pattern may update the
+		// value validated by the next one)
+		sort.Slice(patterns, func(i, j int) bool { return patterns[i].pattern < patterns[j].pattern })
+
 		v.patterns = patterns
 	}
 
 	if x, ok := builder.GetKeyword("additionalProperties"); ok {
-		switch y := x.(type) {
-
-		case map[string]interface{}:
-			s, err := builder.Build("/additionalProperties", y)
-			if err != nil {
-				return err
-			}
-			v.additionalProperties = s
-
-		case bool:
-			if !y {
-				v.additionalProperties = additionalPropertiesDenied
-			}
-
-		default:
-			return fmt.Errorf("invalid 'additionalProperties' definition: %#v", y)
+		if !isSchema(x) {
+			return fmt.Errorf("invalid 'additionalProperties' definition: %#v", x)
+		}
 
+		s, err := builder.Build("/additionalProperties", x)
+		if err != nil {
+			return err
 		}
+		v.additionalProperties = s
 	}
 
 	return nil
@@ -107,41 +106,109 @@ func (v *propertiesValidator) Validate(x interface{}, ctx *Context) {
 
 		if schema, found := v.properties[k]; found {
 			additional = false
-			newValue, err := ctx.ValidateValueWith(m, schema)
+			newValue, err := ctx.ValidatePropertyWith(k, m, schema)
 			if err != nil {
-				ctx.Report(&ErrInvalidProperty{k, err})
+				ctx.Report(&ErrInvalidProperty{Property: k, Err: err})
 			} else {
 				m = newValue
-				y[k] = newValue
+				ctx.setProperty(k, newValue)
 			}
 		}
 
 		for _, pattern := range v.patterns {
 			if pattern.regexp.MatchString(k) {
 				additional = false
-				newValue, err := ctx.ValidateValueWith(m, pattern.schema)
+				newValue, err := ctx.ValidatePropertyWith(k, m, pattern.schema)
 				if err != nil {
-					ctx.Report(&ErrInvalidProperty{k, err})
+					ctx.Report(&ErrInvalidProperty{Property: k, Err: err})
 				} else {
 					m = newValue
-					y[k] = newValue
+					ctx.setProperty(k, newValue)
 				}
 			}
 		}
 
 		if additional {
-			if v.additionalProperties == additionalPropertiesDenied {
-				ctx.Report(&ErrInvalidProperty{k, fmt.Errorf("additional property is not allowed")})
-			} else if v.additionalProperties != nil {
-				newValue, err := ctx.ValidateValueWith(m, v.additionalProperties)
-				if err != nil {
-					ctx.Report(&ErrInvalidProperty{k, err})
+			if v.removeAdditional(ctx) {
+				ctx.removeProperty(k)
+				continue
+			}
+
+			if v.additionalProperties != nil {
+				newValue, err := ctx.ValidatePropertyWith(k, m, v.additionalProperties)
+				if err != nil && ctx.removeAdditional == RemoveFailingAdditional {
+					ctx.removeProperty(k)
+					continue
+				} else if err != nil {
+					ctx.Report(&ErrInvalidProperty{Property: k, Err: err})
 				} else {
 					m = newValue
-					y[k] = newValue
+					ctx.setProperty(k, newValue)
+				}
+			}
+		}
+
+		if !additional || v.additionalProperties != nil {
+			ctx.markPropertyEvaluated(k)
+		}
+	}
+}
+
+// removeAdditional returns true when the additional properties must be
+// removed without validating them.
+func (v *propertiesValidator) removeAdditional(ctx *Context) bool {
+	switch ctx.removeAdditional {
+	case RemoveAllAdditional:
+		return true
+	case RemoveDeniedAdditional:
+		s := v.additionalProperties
+		return s != nil && s.Bool != nil && !*s.Bool
+	default:
+		return false
+	}
+}
+
+func (v *requiredPropertiesValidator) Setup(builder Builder) error {
+	err := v.propertiesValidator.Setup(builder)
+	if err != nil {
+		return err
+	}
+
+	if x, found := builder.GetKeyword("properties"); found {
+		defs, _ := x.(map[string]interface{})
+		for k, y := range defs {
+			mdef, ok := y.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if x, found := mdef["required"]; found {
+				required, ok := x.(bool)
+				if !ok {
+					return fmt.Errorf("invalid 'required' definition: %#v", x)
+				}
+				if required {
+					v.required = append(v.required, k)
 				}
 			}
 		}
+		sort.Strings(v.required)
+	}
+
+	return nil
+}
+
+func (v *requiredPropertiesValidator) Validate(x interface{}, ctx *Context) {
+	v.propertiesValidator.Validate(x, ctx)
 
+	y, ok := x.(map[string]interface{})
+	if !ok || y == nil {
+		return
+	}
+
+	for _, k := range v.required {
+		if _, found := y[k]; !found {
+			ctx.Report(&ErrRequiredProperty{Property: k})
+		}
 	}
 }
@@ -0,0 +1,39 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type propertyNamesValidator struct {
+	schema *Schema
+}
+
+func (v *propertyNamesValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("propertyNames"); found {
+		if !isSchema(x) {
+			return fmt.Errorf("invalid 'propertyNames' definition: %#v", x)
+		}
+
+		schema, err := builder.Build("/propertyNames", x)
+		if err != nil {
+			return err
+		}
+
+		v.schema = schema
+	}
+	return nil
+}
+
+func (v *propertyNamesValidator) Validate(x interface{}, ctx *Context) {
+	y, ok := x.(map[string]interface{})
+	if !ok || y == nil {
+		return
+	}
+
+	for k := range y {
+		_, err := ctx.ValidateValueWith(k, v.schema)
+		if err != nil {
+			ctx.Report(&ErrInvalidPropertyName{Property: k, Err: err})
+		}
+	}
+}
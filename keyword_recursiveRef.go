@@ -0,0 +1,36 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+// recursiveRefValidator implements the draft 2019-09 `$recursiveRef`
+// keyword. It behaves like `$dynamicRef` where `"$recursiveAnchor": true`
+// acts as an anonymous dynamic anchor.
+type recursiveRefValidator struct {
+	schema *Schema
+}
+
+func (v *recursiveRefValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("$recursiveRef"); found {
+		y, ok := x.(string)
+		if !ok {
+			return fmt.Errorf("invalid '$recursiveRef' definition: %#v", x)
+		}
+
+		schema, err := builder.Reference(y)
+		if err != nil {
+			return err
+		}
+
+		v.schema = schema
+	}
+	return nil
+}
+
+func (v *recursiveRefValidator) Validate(x interface{}, ctx *Context) {
+	_, err := ctx.validateSelfWith(resolveDynamicRef(ctx, v.schema, "", true), true)
+	if err != nil {
+		ctx.Report(err)
+	}
+}
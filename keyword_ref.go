@@ -0,0 +1,36 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+// refValidator implements the `$ref` keyword of draft 2019-09 and later
+// where, unlike in earlier drafts, `$ref` is applied next to the other
+// keywords of the schema.
+type refValidator struct {
+	schema *Schema
+}
+
+func (v *refValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("$ref"); found {
+		y, ok := x.(string)
+		if !ok {
+			return fmt.Errorf("invalid '$ref' definition: %#v", x)
+		}
+
+		schema, err := builder.Reference(y)
+		if err != nil {
+			return err
+		}
+
+		v.schema = schema
+	}
+	return nil
+}
+
+func (v *refValidator) Validate(x interface{}, ctx *Context) {
+	_, err := ctx.validateSelfWith(v.schema, true)
+	if err != nil {
+		ctx.Report(err)
+	}
+}
@@ -42,7 +42,7 @@ func (v *requiredValidator) Validate(x interface{}, ctx *Context) {
 	for _, k := range v.required {
 		_, found := y[k]
 		if !found {
-			ctx.Report(&ErrRequiredProperty{k})
+			ctx.Report(&ErrRequiredProperty{Property: k})
 		}
 	}
 }
@@ -3,12 +3,35 @@ package jsonschema
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
 )
 
 type typeValidator struct {
 	expects []PrimitiveType
 }
 
+// integralTypeValidator implements the draft-06 `type` keyword which also
+// accepts numbers with a zero fractional part (like 1.0) as integers.
+type integralTypeValidator struct {
+	typeValidator
+}
+
+// nullableTypeValidator implements the `type` and `nullable` keywords of the
+// OpenAPI 3.0 schema object where `"nullable": true` also allows null.
+type nullableTypeValidator struct {
+	typeValidator
+	nullable bool
+}
+
+// unionTypeValidator implements the draft-03 `type` keyword where the union
+// may also contain "any" and schemas.
+type unionTypeValidator struct {
+	any     bool
+	expects []PrimitiveType
+	schemas []*Schema
+}
+
 func (v *typeValidator) Setup(builder Builder) error {
 	if x, found := builder.GetKeyword("type"); found {
 		switch y := x.(type) {
@@ -46,66 +69,256 @@ func (v *typeValidator) Setup(builder Builder) error {
 	return nil
 }
 
-func (v *typeValidator) Validate(x interface{}, ctx *Context) {
-	for _, t := range v.expects {
-		switch t {
-		case ArrayType:
-			if _, ok := x.([]interface{}); ok && x != nil {
-				return
-			}
+func (v *nullableTypeValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("nullable"); found {
+		y, ok := x.(bool)
+		if !ok {
+			return fmt.Errorf("invalid 'nullable' definition: %#v", x)
+		}
+		v.nullable = y
+	}
 
-		case BooleanType:
-			if _, ok := x.(bool); ok {
-				return
-			}
+	return v.typeValidator.Setup(builder)
+}
 
-		case IntegerType:
-			if y, ok := x.(json.Number); ok {
-				i, err := y.Int64()
-				if err == nil {
-					ctx.UpdateValue(i)
-					return
-				}
-			}
-			if _, ok := x.(int64); ok {
-				return
+func (v *unionTypeValidator) Setup(builder Builder) error {
+	return v.setup(builder, "type")
+}
+
+func (v *unionTypeValidator) setup(builder Builder, keyword string) error {
+	x, found := builder.GetKeyword(keyword)
+	if !found {
+		return nil
+	}
+
+	var union []interface{}
+	switch y := x.(type) {
+	case string:
+		union = []interface{}{y}
+	case []interface{}:
+		union = y
+	default:
+		return fmt.Errorf("invalid '%s' definition: %#v", keyword, x)
+	}
+
+	for i, a := range union {
+		switch b := a.(type) {
+		case string:
+			if b == "any" {
+				v.any = true
+				continue
 			}
 
-		case NullType:
-			if x == nil {
-				return
+			t := PrimitiveType(b)
+			if !t.Valid() {
+				return fmt.Errorf("invalid '%s' definition: %#v", keyword, x)
 			}
+			v.expects = append(v.expects, t)
 
-		case NumberType:
-			if y, ok := x.(json.Number); ok {
-				f, err := y.Float64()
-				if err == nil {
-					ctx.UpdateValue(f)
-					return
-				}
+		case map[string]interface{}:
+			schema, err := builder.Build(fmt.Sprintf("/%s/%d", keyword, i), b)
+			if err != nil {
+				return err
 			}
-			if _, ok := x.(float64); ok {
-				return
+			v.schemas = append(v.schemas, schema)
+
+		default:
+			return fmt.Errorf("invalid '%s' definition: %#v", keyword, x)
+		}
+	}
+
+	return nil
+}
+
+func (v *typeValidator) Validate(x interface{}, ctx *Context) {
+	v.validate(x, ctx, false)
+}
+
+func (v *integralTypeValidator) Validate(x interface{}, ctx *Context) {
+	v.validate(x, ctx, true)
+}
+
+func (v *nullableTypeValidator) Validate(x interface{}, ctx *Context) {
+	if x == nil && v.nullable {
+		return
+	}
+	if len(v.expects) == 0 {
+		return
+	}
+	v.validate(x, ctx, false)
+}
+
+func (v *unionTypeValidator) Validate(x interface{}, ctx *Context) {
+	if !v.matches(x, ctx) && !coerceType(v.expects, x, ctx, false) {
+		ctx.Report(&ErrInvalidType{Types: v.expects})
+	}
+}
+
+// matches returns true when x is of one of the types of the union. Values
+// aren't coerced so `disallow` doesn't reject the strings it could convert.
+func (v *unionTypeValidator) matches(x interface{}, ctx *Context) bool {
+	if v.any {
+		return true
+	}
+
+	for _, t := range v.expects {
+		if matchType(t, x, ctx, false) {
+			return true
+		}
+	}
+
+	for _, schema := range v.schemas {
+		if _, err := ctx.ValidateSelfWith(schema); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *typeValidator) validate(x interface{}, ctx *Context, integral bool) {
+	for _, t := range v.expects {
+		if matchType(t, x, ctx, integral) {
+			return
+		}
+	}
+
+	if coerceType(v.expects, x, ctx, integral) {
+		return
+	}
+
+	ctx.Report(&ErrInvalidType{Types: v.expects})
+}
+
+// coerceType converts x to the first of the types it can be converted to
+// (when coercion is enabled) and updates the value in the context.
+func coerceType(expects []PrimitiveType, x interface{}, ctx *Context, integral bool) bool {
+	if !ctx.coerce {
+		return false
+	}
+
+	for _, t := range expects {
+		y, ok := coerceValue(t, x, ctx.coerceArrays)
+		if ok && matchType(t, y, ctx, integral) {
+			ctx.UpdateValue(y)
+			return true
+		}
+	}
+
+	return false
+}
+
+// coerceValue converts x to a value of type t. Only strings are converted
+// (and scalars to arrays when arrays is set).
+func coerceValue(t PrimitiveType, x interface{}, arrays bool) (interface{}, bool) {
+	if t == ArrayType {
+		switch x.(type) {
+		case []interface{}, map[string]interface{}:
+			return nil, false
+		}
+		return []interface{}{x}, arrays
+	}
+
+	s, ok := x.(string)
+	if !ok {
+		return nil, false
+	}
+
+	switch t {
+	case BooleanType:
+		switch s {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+
+	case IntegerType:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, true
+		}
+
+	case NullType:
+		if s == "" {
+			return nil, true
+		}
+
+	case NumberType:
+		// only accept JSON numbers (strconv also accepts "Inf", "0x1p4", ...)
+		if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) || !json.Valid([]byte(s)) {
+			return nil, false
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
+func matchType(t PrimitiveType, x interface{}, ctx *Context, integral bool) bool {
+	switch t {
+	case ArrayType:
+		if _, ok := x.([]interface{}); ok && x != nil {
+			return true
+		}
+
+	case BooleanType:
+		if _, ok := x.(bool); ok {
+			return true
+		}
+
+	case IntegerType:
+		if y, ok := x.(json.Number); ok {
+			i, err := y.Int64()
+			if err == nil {
+				ctx.UpdateValue(i)
+				return true
 			}
-			if y, ok := x.(int64); ok {
-				ctx.UpdateValue(float64(y))
-				return
+		}
+		if _, ok := x.(int64); ok {
+			return true
+		}
+		if integral {
+			if f, ok, err := toFloat(x); ok && err == nil && f == math.Trunc(f) {
+				return true
 			}
+		}
 
-		case ObjectType:
-			if _, ok := x.(map[string]interface{}); ok && x != nil {
-				return
-			}
+	case NullType:
+		if x == nil {
+			return true
+		}
 
-		case StringType:
-			if _, ok := x.(string); ok {
-				return
+	case NumberType:
+		if y, ok := x.(json.Number); ok {
+			f, err := y.Float64()
+			if err == nil {
+				ctx.UpdateValue(f)
+				return true
 			}
+		}
+		if _, ok := x.(float64); ok {
+			return true
+		}
+		if y, ok := x.(int64); ok {
+			ctx.UpdateValue(float64(y))
+			return true
+		}
 
-		default:
-			panic("invalid type: " + t)
+	case ObjectType:
+		if _, ok := x.(map[string]interface{}); ok && x != nil {
+			return true
 		}
+
+	case StringType:
+		if _, ok := x.(string); ok {
+			return true
+		}
+
+	default:
+		panic("invalid type: " + t)
 	}
 
-	ctx.Report(&ErrInvalidType{expected: v.expects, was: x})
+	return false
 }
@@ -0,0 +1,48 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type unevaluatedItemsValidator struct {
+	schema *Schema
+}
+
+func (v *unevaluatedItemsValidator) dependsOnEvaluation() {}
+
+func (v *unevaluatedItemsValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("unevaluatedItems"); found {
+		if !isSchema(x) {
+			return fmt.Errorf("invalid 'unevaluatedItems' definition: %#v", x)
+		}
+
+		s, err := builder.Build("/unevaluatedItems", x)
+		if err != nil {
+			return err
+		}
+		v.schema = s
+	}
+	return nil
+}
+
+func (v *unevaluatedItemsValidator) Validate(x interface{}, ctx *Context) {
+	y, ok := x.([]interface{})
+	if !ok || y == nil || v.schema == nil {
+		return
+	}
+
+	for i, item := range y {
+		if ctx.isItemEvaluated(i) {
+			continue
+		}
+
+		newValue, err := ctx.ValidateItemWith(i, item, v.schema)
+		if err != nil {
+			ctx.Report(&ErrInvalidItem{Index: i, Err: err})
+			continue
+		}
+		ctx.setItem(i, newValue)
+	}
+
+	ctx.markItemsEvaluated(len(y))
+}
@@ -0,0 +1,48 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+type unevaluatedPropertiesValidator struct {
+	schema *Schema
+}
+
+func (v *unevaluatedPropertiesValidator) dependsOnEvaluation() {}
+
+func (v *unevaluatedPropertiesValidator) Setup(builder Builder) error {
+	if x, found := builder.GetKeyword("unevaluatedProperties"); found {
+		if !isSchema(x) {
+			return fmt.Errorf("invalid 'unevaluatedProperties' definition: %#v", x)
+		}
+
+		s, err := builder.Build("/unevaluatedProperties", x)
+		if err != nil {
+			return err
+		}
+		v.schema = s
+	}
+	return nil
+}
+
+func (v *unevaluatedPropertiesValidator) Validate(x interface{}, ctx *Context) {
+	y, ok := x.(map[string]interface{})
+	if !ok || y == nil || v.schema == nil {
+		return
+	}
+
+	for k, m := range y {
+		if ctx.isPropertyEvaluated(k) {
+			continue
+		}
+
+		newValue, err := ctx.ValidatePropertyWith(k, m, v.schema)
+		if err != nil {
+			ctx.Report(&ErrInvalidProperty{Property: k, Err: err})
+			continue
+		}
+		ctx.setProperty(k, newValue)
+
+		ctx.markPropertyEvaluated(k)
+	}
+}
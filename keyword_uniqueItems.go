@@ -23,7 +23,16 @@ func (v *uniqueItemsValidator) Validate(x interface{}, ctx *Context) {
 		return
 	}
 
+	for _, err := range uniqueItemsErrors(y) {
+		ctx.Report(err)
+	}
+}
+
+// uniqueItemsErrors returns an error for each item of y which repeats an
+// earlier item.
+func uniqueItemsErrors(y []interface{}) []error {
 	var (
+		errs    []error
 		l       = len(y)
 		skipbuf [32]int
 		skip    = skipbuf[:0]
@@ -48,7 +57,7 @@ func (v *uniqueItemsValidator) Validate(x interface{}, ctx *Context) {
 			if err != nil {
 				skip = append(skip, j)
 				sort.Ints(skip)
-				ctx.Report(err)
+				errs = append(errs, err)
 				continue
 			}
 
@@ -56,10 +65,12 @@ func (v *uniqueItemsValidator) Validate(x interface{}, ctx *Context) {
 			if equal {
 				skip = append(skip, j)
 				sort.Ints(skip)
-				ctx.Report(&ErrNotUnique{i, j, a})
+				errs = append(errs, &ErrNotUnique{IndexA: i, IndexB: j, Item: a})
 			}
 		}
 	}
+
+	return errs
 }
 
 func containsInt(s []int, x int) bool {
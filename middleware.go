@@ -0,0 +1,447 @@
+package jsonschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Route selects the schemas of the requests with Method (any method when
+// empty) and Path. Path is a template like `/orders/{id}` where each
+// `{name}` matches one segment of the path.
+type Route struct {
+	Method string
+	Path   string
+
+	// Body is the schema of the JSON body (the body isn't validated when
+	// it is nil).
+	Body *Schema
+
+	// OptionalBody accepts the requests without a body (their body isn't
+	// validated) when Body is set.
+	OptionalBody bool
+
+	// Parameters are the query (In is "query") and path (In is "path")
+	// parameters. The values are strings (or arrays of strings when a
+	// query parameter is repeated) which are converted like
+	// WithArrayCoercion does.
+	Parameters []*OpenAPIParameter
+
+	// Responses are the schemas of the JSON responses by status code (like
+	// "200", "2XX" or "default") and media type (see ContractTransport).
+	Responses map[string]map[string]*Schema
+}
+
+// OpenAPIRoutes returns the routes of the operations of doc with the schema
+// of the JSON request body, the query and path parameters and the responses.
+func OpenAPIRoutes(doc *OpenAPIDocument) []*Route {
+	routes := make([]*Route, 0, len(doc.Operations))
+
+	for _, op := range doc.Operations {
+		route := &Route{
+			Method:       op.Method,
+			Path:         op.Path,
+			OptionalBody: !op.RequestBodyRequired,
+			Responses:    op.Responses,
+		}
+
+		mediaTypes := make([]string, 0, len(op.RequestBody))
+		for mediaType := range op.RequestBody {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		sort.Strings(mediaTypes)
+		for _, mediaType := range mediaTypes {
+			if isJSONMediaType(mediaType) {
+				route.Body = op.RequestBody[mediaType]
+				break
+			}
+		}
+
+		for _, p := range op.Parameters {
+			if p.In == "query" || p.In == "path" {
+				route.Parameters = append(route.Parameters, p)
+			}
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+// RequestValidator is a middleware which validates the requests against the
+// schemas of their route. Invalid requests are rejected with a Problem and
+// the values of valid requests are passed to the next handler (see
+// ValidatedRequestFrom). Requests without a route are passed on as is.
+type RequestValidator struct {
+	Routes []*Route
+
+	// Options are the options of the validation of the bodies (like
+	// WithDefaults).
+	Options []ValidateOption
+
+	// MaxBodySize limits the size of the bodies (there is no limit when it
+	// is 0).
+	MaxBodySize int64
+}
+
+// ValidatedRequest holds the values of a request validated by a
+// RequestValidator (with the updates made during the validation, like the
+// converted parameters).
+type ValidatedRequest struct {
+	Route *Route
+	Body  interface{}
+	Query map[string]interface{}
+	Path  map[string]interface{}
+}
+
+type validatedRequestKey struct{}
+
+// ValidatedRequestFrom returns the values of the request validated by a
+// RequestValidator.
+func ValidatedRequestFrom(ctx context.Context) (*ValidatedRequest, bool) {
+	v, ok := ctx.Value(validatedRequestKey{}).(*ValidatedRequest)
+	return v, ok
+}
+
+// Problem is an RFC 7807 problem details object (served as
+// `application/problem+json`).
+type Problem struct {
+	Type   string `json:"type,omitempty"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+
+	// Errors are the validation errors.
+	Errors []*ProblemError `json:"errors,omitempty"`
+}
+
+// ProblemError is a validation error of a Problem. Pointer is the instance
+// location in the body or the name of a parameter followed by the location
+// in its value (like `/limit`).
+type ProblemError struct {
+	In      string `json:"in"`
+	Pointer string `json:"pointer"`
+	Keyword string `json:"keyword,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+// Handler returns the middleware for next.
+func (v *RequestValidator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathValues := matchRoute(v.Routes, r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result := &ValidatedRequest{Route: route}
+
+		var errs []*ProblemError
+		result.Query, result.Path, errs = validateParameters(route, r, pathValues)
+
+		if route.Body != nil && !(route.OptionalBody && emptyBody(r)) {
+			body, problem := v.validateBody(route.Body, r)
+			if problem != nil {
+				problem.Errors = append(errs, problem.Errors...)
+				writeProblem(w, problem)
+				return
+			}
+			result.Body = body
+		}
+
+		if len(errs) > 0 {
+			writeProblem(w, &Problem{
+				Title:  "Invalid request",
+				Status: http.StatusBadRequest,
+				Errors: errs,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), validatedRequestKey{}, result)))
+	})
+}
+
+// matchRoute returns the most specific of routes which matches r and the
+// values of its path parameters. Like in OpenAPI, literal segments are more
+// specific than parameters (so `/orders/search` is preferred over
+// `/orders/{id}`); then routes with a method are preferred over those
+// without. Otherwise the first route wins.
+func matchRoute(routes []*Route, r *http.Request) (*Route, map[string]string) {
+	var (
+		match  *Route
+		values map[string]string
+	)
+
+	for _, route := range routes {
+		if route.Method != "" && !strings.EqualFold(route.Method, r.Method) {
+			continue
+		}
+		if v, ok := matchPath(route.Path, r.URL.EscapedPath()); ok && (match == nil || moreSpecific(route, match)) {
+			match, values = route, v
+		}
+	}
+
+	return match, values
+}
+
+// moreSpecific returns true when a is more specific than b (which match the
+// same path).
+func moreSpecific(a, b *Route) bool {
+	var (
+		x = strings.Split(strings.Trim(a.Path, "/"), "/")
+		y = strings.Split(strings.Trim(b.Path, "/"), "/")
+	)
+
+	for i := range x {
+		if p, q := isPathParameter(x[i]), isPathParameter(y[i]); p != q {
+			return q
+		}
+	}
+
+	return a.Method != "" && b.Method == ""
+}
+
+// isPathParameter returns true for the `{name}` segments of a path template.
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// matchPath matches the escaped path against the template. The segments
+// are unescaped after the path is split (so a `%2F` in a parameter doesn't
+// split it).
+func matchPath(template, path string) (map[string]string, bool) {
+	var (
+		want   = strings.Split(strings.Trim(template, "/"), "/")
+		have   = strings.Split(strings.Trim(path, "/"), "/")
+		values = map[string]string{}
+	)
+
+	if len(want) != len(have) {
+		return nil, false
+	}
+
+	for i, segment := range want {
+		value, err := url.PathUnescape(have[i])
+		if err != nil {
+			return nil, false
+		}
+		if isPathParameter(segment) && value != "" {
+			values[segment[1:len(segment)-1]] = value
+			continue
+		}
+		if segment != value {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
+// validateParameters validates the query and path parameters of route.
+func validateParameters(route *Route, r *http.Request, pathValues map[string]string) (query, path map[string]interface{}, errs []*ProblemError) {
+	values := r.URL.Query()
+
+	for _, p := range route.Parameters {
+		var x interface{}
+
+		switch p.In {
+		case "query":
+			v, found := values[p.Name]
+			if !found {
+				if p.Required {
+					errs = append(errs, &ProblemError{In: p.In, Pointer: "/" + escapeJSONPointer(p.Name), Keyword: "required", Detail: "missing required parameter"})
+				}
+				continue
+			}
+			if len(v) == 1 {
+				x = v[0]
+			} else {
+				items := make([]interface{}, len(v))
+				for i, s := range v {
+					items[i] = s
+				}
+				x = items
+			}
+
+		case "path":
+			v, found := pathValues[p.Name]
+			if !found {
+				continue
+			}
+			x = v
+
+		default:
+			continue
+		}
+
+		if p.Schema != nil {
+			var err error
+			x, err = p.Schema.ValidateValue(x, WithArrayCoercion())
+			if err != nil {
+				errs = append(errs, problemErrors(p.In, "/"+escapeJSONPointer(p.Name), err)...)
+				continue
+			}
+		}
+
+		if p.In == "query" {
+			if query == nil {
+				query = map[string]interface{}{}
+			}
+			query[p.Name] = x
+		} else {
+			if path == nil {
+				path = map[string]interface{}{}
+			}
+			path[p.Name] = x
+		}
+	}
+
+	return query, path, errs
+}
+
+// emptyBody returns true when r has no body. Bodies of unknown length (like
+// chunked bodies) are peeked at; the byte which was read is put back.
+func emptyBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+	if r.ContentLength > 0 {
+		return false
+	}
+
+	var b [1]byte
+	n, err := io.ReadFull(r.Body, b[:])
+	if n == 0 && err == io.EOF {
+		return true
+	}
+
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b[:n]), r.Body), r.Body}
+	return false
+}
+
+// validateBody reads and validates the JSON body of r. The body of r is
+// replaced so the next handler can read it again.
+func (v *RequestValidator) validateBody(schema *Schema, r *http.Request) (interface{}, *Problem) {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || !isJSONMediaType(mediaType) {
+		return nil, &Problem{
+			Title:  "Unsupported media type",
+			Status: http.StatusUnsupportedMediaType,
+			Detail: "the body must be a JSON document",
+		}
+	}
+
+	var reader io.Reader = r.Body
+	if v.MaxBodySize > 0 {
+		reader = io.LimitReader(r.Body, v.MaxBodySize+1)
+	}
+	data, err := ioutil.ReadAll(reader)
+	r.Body.Close()
+	if err != nil {
+		return nil, &Problem{Title: "Invalid request", Status: http.StatusBadRequest, Detail: err.Error()}
+	}
+	if v.MaxBodySize > 0 && int64(len(data)) > v.MaxBodySize {
+		return nil, &Problem{
+			Title:  "Request entity too large",
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: fmt.Sprintf("the body is larger than %d bytes", v.MaxBodySize),
+		}
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	var x interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = dec.Decode(&x)
+	if err != nil {
+		return nil, &Problem{Title: "Invalid JSON", Status: http.StatusBadRequest, Detail: err.Error()}
+	}
+
+	x, err = validateInPlace(schema, x, v.Options)
+	if err != nil {
+		return nil, &Problem{
+			Title:  "Invalid request",
+			Status: http.StatusBadRequest,
+			Errors: problemErrors("body", "", err),
+		}
+	}
+
+	return x, nil
+}
+
+// problemErrors returns the leaf errors of err (which is an error of the
+// value at pointer).
+func problemErrors(in, pointer string, err error) []*ProblemError {
+	var errs []*ProblemError
+	for _, err := range leafErrors(err) {
+		p := &ProblemError{In: in, Pointer: pointer, Detail: outputMessage(err)}
+		if e, ok := err.(Error); ok {
+			p.Pointer += e.InstanceLocation()
+			p.Keyword = e.Keyword()
+		}
+		errs = append(errs, p)
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Pointer < errs[j].Pointer
+	})
+	return errs
+}
+
+// leafErrors returns the errors nested in err which have no nested errors
+// themselves.
+func leafErrors(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		if nested := e.Unwrap(); len(nested) > 0 {
+			var leaves []error
+			for _, err := range nested {
+				leaves = append(leaves, leafErrors(err)...)
+			}
+			return leaves
+		}
+	case interface{ Unwrap() error }:
+		if nested := e.Unwrap(); nested != nil {
+			return leafErrors(nested)
+		}
+	}
+	return []error{err}
+}
+
+func writeProblem(w http.ResponseWriter, problem *Problem) {
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	w.Write(data)
+}
+
+// isJSONMediaType returns true for `application/json` and the `+json` media
+// types (with or without parameters).
+func isJSONMediaType(mediaType string) bool {
+	if i := strings.IndexByte(mediaType, ';'); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,434 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// OpenAPIDocument holds the schemas of an OpenAPI 3.0/3.1 or Swagger 2.0
+// document.
+type OpenAPIDocument struct {
+	// Schemas are the schemas in `components/schemas` (or `definitions` for
+	// Swagger 2.0) by name.
+	Schemas map[string]*Schema
+
+	Operations []*OpenAPIOperation
+}
+
+// OpenAPIOperation holds the schemas of an operation.
+type OpenAPIOperation struct {
+	Method      string
+	Path        string
+	OperationID string
+	Parameters  []*OpenAPIParameter
+
+	// RequestBody has the schemas of the request body by media type.
+	RequestBody map[string]*Schema
+
+	// RequestBodyRequired is set when the request body is required (by
+	// `requestBody.required` or by a required Swagger 2.0 body parameter).
+	RequestBodyRequired bool
+
+	// Responses has the schemas of the responses by status code (like "200"
+	// or "default") and media type. The statuses of the responses without a
+	// schema have no media types.
+	Responses map[string]map[string]*Schema
+}
+
+// OpenAPIParameter holds the schema of a (non-body) parameter.
+type OpenAPIParameter struct {
+	Name     string
+	In       string
+	Required bool
+	Schema   *Schema
+}
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// swaggerParameterKeywords are the keywords of a Swagger 2.0 non-body
+// parameter which make up its schema.
+var swaggerParameterKeywords = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum",
+	"minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern",
+	"maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+// Operation returns the operation for method and (templated) path.
+func (d *OpenAPIDocument) Operation(method, path string) *OpenAPIOperation {
+	method = strings.ToUpper(method)
+	for _, op := range d.Operations {
+		if op.Method == method && op.Path == path {
+			return op
+		}
+	}
+	return nil
+}
+
+// LoadOpenAPI builds all the schemas of an OpenAPI 3.0/3.1 or Swagger 2.0
+// document. When id is not empty the document is registered in the Env so
+// other schemas can refer to its schemas. Swagger 2.0 schemas are build with
+// the OpenAPI 3.0 dialect.
+func (e *Env) LoadOpenAPI(id string, data []byte) (*OpenAPIDocument, error) {
+	var doc map[string]interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err := dec.Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &openAPILoader{doc: doc, defs: map[string]interface{}{}}
+
+	var dialectURI string
+	if v, ok := doc["swagger"].(string); ok && strings.HasPrefix(v, "2.") {
+		l.swagger = true
+		dialectURI = OpenAPI3Dialect
+	} else if v, ok := doc["openapi"].(string); ok && strings.HasPrefix(v, "3.0") {
+		dialectURI = OpenAPI3Dialect
+	} else if v, ok := doc["openapi"].(string); ok && strings.HasPrefix(v, "3.1") {
+		dialectURI = OpenAPI31Dialect
+		if v, ok := doc["jsonSchemaDialect"].(string); ok {
+			dialectURI = v
+		}
+	} else {
+		return nil, fmt.Errorf("unsupported OpenAPI document (missing `openapi` or `swagger` version)")
+	}
+
+	dialect, found := e.dialects[normalizeRef(dialectURI)]
+	if !found {
+		return nil, fmt.Errorf("unknown dialect: %s", dialectURI)
+	}
+
+	result := &OpenAPIDocument{Schemas: map[string]*Schema{}}
+	names := map[string]string{}
+
+	componentsPointer := "/components/schemas"
+	if l.swagger {
+		componentsPointer = "/definitions"
+	}
+	if x, err := lookupJSONPointer(doc, componentsPointer); err == nil {
+		components, ok := x.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %q: %#v", componentsPointer, x)
+		}
+		for name, def := range components {
+			pointer := componentsPointer + "/" + escapeJSONPointer(name)
+			l.defs[pointer] = def
+			names[name] = pointer
+		}
+	}
+
+	err = l.loadOperations()
+	if err != nil {
+		return nil, err
+	}
+
+	builder := newBuilder(e, dialect)
+	schemas, err := builder.buildDocument(id, l.defs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = builder.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	if id != "" {
+		root := builder.references[normalizeRef(id)]
+		e.schemas[normalizeRef(root.Id.String())] = root
+	}
+
+	for name, pointer := range names {
+		result.Schemas[name] = schemas[pointer]
+	}
+
+	for _, op := range l.operations {
+		o := &OpenAPIOperation{
+			Method:              op.method,
+			Path:                op.path,
+			OperationID:         op.operationID,
+			RequestBodyRequired: op.requestBodyRequired,
+		}
+
+		for _, p := range op.parameters {
+			o.Parameters = append(o.Parameters, &OpenAPIParameter{
+				Name:     p.name,
+				In:       p.in,
+				Required: p.required,
+				Schema:   schemas[p.schema],
+			})
+		}
+
+		if len(op.requestBody) > 0 {
+			o.RequestBody = make(map[string]*Schema, len(op.requestBody))
+			for mediaType, pointer := range op.requestBody {
+				o.RequestBody[mediaType] = schemas[pointer]
+			}
+		}
+
+		if len(op.responses) > 0 {
+			o.Responses = make(map[string]map[string]*Schema, len(op.responses))
+			for status, content := range op.responses {
+				o.Responses[status] = make(map[string]*Schema, len(content))
+				for mediaType, pointer := range content {
+					o.Responses[status][mediaType] = schemas[pointer]
+				}
+			}
+		}
+
+		result.Operations = append(result.Operations, o)
+	}
+
+	return result, nil
+}
+
+// openAPILoader collects the schema definitions of an OpenAPI document. The
+// operations refer to the definitions by their JSON pointer.
+type openAPILoader struct {
+	doc        map[string]interface{}
+	swagger    bool
+	defs       map[string]interface{}
+	operations []*openAPIOperation
+}
+
+type openAPIOperation struct {
+	method              string
+	path                string
+	operationID         string
+	parameters          []*openAPIParameter
+	requestBody         map[string]string
+	requestBodyRequired bool
+	responses           map[string]map[string]string
+}
+
+type openAPIParameter struct {
+	name     string
+	in       string
+	required bool
+	schema   string
+}
+
+func (l *openAPILoader) loadOperations() error {
+	paths, _ := l.doc["paths"].(map[string]interface{})
+
+	keys := make([]string, 0, len(paths))
+	for path := range paths {
+		keys = append(keys, path)
+	}
+	sort.Strings(keys)
+
+	for _, path := range keys {
+		pointer, x, err := l.resolve("/paths/"+escapeJSONPointer(path), paths[path])
+		if err != nil {
+			return err
+		}
+		item, ok := x.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid path item %q: %#v", path, x)
+		}
+
+		for _, method := range openAPIMethods {
+			def, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			op := &openAPIOperation{
+				method:      strings.ToUpper(method),
+				path:        path,
+				requestBody: map[string]string{},
+				responses:   map[string]map[string]string{},
+			}
+			op.operationID, _ = def["operationId"].(string)
+
+			err := l.loadParameters(op, def, pointer+"/parameters", item["parameters"])
+			if err != nil {
+				return err
+			}
+
+			opPointer := pointer + "/" + method
+			err = l.loadParameters(op, def, opPointer+"/parameters", def["parameters"])
+			if err != nil {
+				return err
+			}
+
+			if x, found := def["requestBody"]; found {
+				pointer, x, err := l.resolve(opPointer+"/requestBody", x)
+				if err != nil {
+					return err
+				}
+				op.requestBody = l.loadContent(pointer, x)
+				if def, ok := x.(map[string]interface{}); ok {
+					op.requestBodyRequired, _ = def["required"].(bool)
+				}
+			}
+
+			responses, _ := def["responses"].(map[string]interface{})
+			for status, x := range responses {
+				pointer, x, err := l.resolve(opPointer+"/responses/"+escapeJSONPointer(status), x)
+				if err != nil {
+					return err
+				}
+
+				var content map[string]string
+				if l.swagger {
+					content = l.loadSwaggerBody(def, "produces", pointer, x)
+				} else {
+					content = l.loadContent(pointer, x)
+				}
+				op.responses[status] = content
+			}
+
+			l.operations = append(l.operations, op)
+		}
+	}
+
+	return nil
+}
+
+// loadParameters adds the parameters to op. Parameters with the same name
+// and location replace those added before (like the path item parameters).
+func (l *openAPILoader) loadParameters(op *openAPIOperation, opDef map[string]interface{}, pointer string, x interface{}) error {
+	params, _ := x.([]interface{})
+	for i, x := range params {
+		pointer, x, err := l.resolve(fmt.Sprintf("%s/%d", pointer, i), x)
+		if err != nil {
+			return err
+		}
+		def, ok := x.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid parameter at %q: %#v", pointer, x)
+		}
+
+		p := &openAPIParameter{}
+		p.name, _ = def["name"].(string)
+		p.in, _ = def["in"].(string)
+		p.required, _ = def["required"].(bool)
+
+		switch {
+		case l.swagger && p.in == "body":
+			op.requestBody = l.loadSwaggerBody(opDef, "consumes", pointer, def)
+			op.requestBodyRequired = p.required
+			continue
+
+		case l.swagger:
+			if def["type"] != "file" {
+				schema := map[string]interface{}{}
+				for _, k := range swaggerParameterKeywords {
+					if v, found := def[k]; found {
+						schema[k] = v
+					}
+				}
+				p.schema = pointer
+				l.defs[pointer] = schema
+			}
+
+		case def["schema"] != nil:
+			p.schema = pointer + "/schema"
+			l.defs[p.schema] = def["schema"]
+
+		default:
+			for _, schema := range l.loadContent(pointer, def) {
+				p.schema = schema
+				break
+			}
+		}
+
+		replaced := false
+		for j, q := range op.parameters {
+			if q.name == p.name && q.in == p.in {
+				op.parameters[j] = p
+				replaced = true
+			}
+		}
+		if !replaced {
+			op.parameters = append(op.parameters, p)
+		}
+	}
+
+	return nil
+}
+
+// loadContent adds the schemas of the media types in the `content` of an
+// OpenAPI 3 request body, response or parameter.
+func (l *openAPILoader) loadContent(pointer string, x interface{}) map[string]string {
+	def, _ := x.(map[string]interface{})
+	content, _ := def["content"].(map[string]interface{})
+
+	schemas := make(map[string]string, len(content))
+	for mediaType, y := range content {
+		media, _ := y.(map[string]interface{})
+		if schema, found := media["schema"]; found {
+			p := pointer + "/content/" + escapeJSONPointer(mediaType) + "/schema"
+			l.defs[p] = schema
+			schemas[mediaType] = p
+		}
+	}
+	return schemas
+}
+
+// loadSwaggerBody adds the `schema` of a Swagger 2.0 body parameter or
+// response for each of the media types the operation consumes or produces.
+func (l *openAPILoader) loadSwaggerBody(op map[string]interface{}, key string, pointer string, x interface{}) map[string]string {
+	def, _ := x.(map[string]interface{})
+	schema, found := def["schema"]
+	if !found {
+		return nil
+	}
+
+	p := pointer + "/schema"
+	l.defs[p] = schema
+
+	mediaTypes, _ := op[key].([]interface{})
+	if mediaTypes == nil {
+		mediaTypes, _ = l.doc[key].([]interface{})
+	}
+	if mediaTypes == nil {
+		mediaTypes = []interface{}{"application/json"}
+	}
+
+	schemas := make(map[string]string, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		if s, ok := mediaType.(string); ok {
+			schemas[s] = p
+		}
+	}
+	return schemas
+}
+
+// resolve follows the (local) Reference Objects in the document.
+func (l *openAPILoader) resolve(pointer string, x interface{}) (string, interface{}, error) {
+	for i := 0; i < 32; i++ {
+		def, ok := x.(map[string]interface{})
+		if !ok {
+			return pointer, x, nil
+		}
+
+		ref, ok := def["$ref"].(string)
+		if !ok {
+			return pointer, x, nil
+		}
+		if !strings.HasPrefix(ref, "#") {
+			return "", nil, fmt.Errorf("unsupported reference at %q: %s", pointer, ref)
+		}
+
+		fragment, err := url.PathUnescape(ref[1:])
+		if err != nil {
+			return "", nil, err
+		}
+
+		pointer = fragment
+		x, err = lookupJSONPointer(l.doc, pointer)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	return "", nil, fmt.Errorf("reference loop at %q", pointer)
+}
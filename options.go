@@ -0,0 +1,168 @@
+package jsonschema
+
+import "strconv"
+
+// ValidateOption enables an optional mode of validation. Some modes update
+// the instance; use Schema.ValidateValue to get the updated instance.
+type ValidateOption func(c *Context)
+
+// WithDefaults fills in missing properties (and the missing items of
+// tuples) from the `default` of their schemas. The defaults are validated
+// like any other value.
+func WithDefaults() ValidateOption {
+	return func(c *Context) { c.defaults = true }
+}
+
+// WithTypeCoercion converts strings to the numbers, integers, booleans and
+// null required by the `type` of a schema (like "42" to 42 for
+// `"type": "integer"`). Values are only converted when they don't match any
+// of the types.
+func WithTypeCoercion() ValidateOption {
+	return func(c *Context) { c.coerce = true }
+}
+
+// WithArrayCoercion enables WithTypeCoercion and also wraps values in a
+// single-element array when the `type` requires an array.
+func WithArrayCoercion() ValidateOption {
+	return func(c *Context) {
+		c.coerce = true
+		c.coerceArrays = true
+	}
+}
+
+// WithFormatAssertion makes the `format` keyword of drafts 2019-09 and
+// 2020-12 an assertion (like in the older drafts) instead of an annotation.
+func WithFormatAssertion() ValidateOption {
+	return func(c *Context) { c.assertFormat = true }
+}
+
+// RemoveAdditionalMode selects the additional properties which are removed
+// by WithRemoveAdditional.
+type RemoveAdditionalMode int
+
+const (
+	// RemoveAllAdditional removes all the properties which are not matched
+	// by `properties` or `patternProperties` of a schema which has one of
+	// the properties keywords (whatever `additionalProperties` is). Each
+	// schema only knows its own properties: with
+	// `"allOf": [{"properties": {"a": {}}}, {"properties": {"b": {}}}]`
+	// the first part removes b and the second one removes a, so the
+	// properties must also be listed in the schema with the `allOf`.
+	RemoveAllAdditional RemoveAdditionalMode = iota + 1
+
+	// RemoveDeniedAdditional removes the additional properties of a schema
+	// with `"additionalProperties": false`.
+	RemoveDeniedAdditional
+
+	// RemoveFailingAdditional removes the additional properties which are
+	// not valid for `additionalProperties` (including `false`).
+	RemoveFailingAdditional
+)
+
+// WithRemoveAdditional removes additional properties instead of reporting
+// them as errors. removed (when not nil) is called with the instance
+// location of each of the removed properties.
+func WithRemoveAdditional(mode RemoveAdditionalMode, removed func(instanceLocation string)) ValidateOption {
+	return func(c *Context) {
+		c.removeAdditional = mode
+		c.onRemove = removed
+	}
+}
+
+// defaultsInjector is implemented by validators which fill in the missing
+// members of a value from the defaults of their subschemas.
+type defaultsInjector interface {
+	injectDefaults(x interface{}, ctx *Context)
+}
+
+// defaultValue returns the `default` of schema (following its references).
+func defaultValue(schema *Schema) (interface{}, bool) {
+	for i := 0; schema != nil && i < 32; i++ {
+		for j := 0; schema.RefSchema != nil && j < 32; j++ {
+			schema = schema.RefSchema
+		}
+
+		if x, found := schema.Definition["default"]; found {
+			return x, true
+		}
+
+		var next *Schema
+		for _, validator := range schema.Validators {
+			if v, ok := validator.(*refValidator); ok {
+				next = v.schema
+			}
+		}
+		schema = next
+	}
+	return nil, false
+}
+
+// injectingDefault returns true when the current value is part of a default
+// filled in for schema, so the recursive defaults (like the `{}` defaults of
+// a meta-schema) are filled in only once.
+func (c *Context) injectingDefault(schema *Schema) bool {
+	for schema.RefSchema != nil {
+		schema = schema.RefSchema
+	}
+	for i := len(c.stack) - 1; i >= 0 && c.stack[i].inDefault; i-- {
+		if c.stack[i].schema == schema {
+			return true
+		}
+	}
+	return false
+}
+
+// markInjected records that the member of the current value at token (see
+// contextStackFrame.instanceToken) was filled in from a default.
+func (c *Context) markInjected(token string) {
+	frame := &c.stack[len(c.stack)-1]
+	if frame.injected == nil {
+		frame.injected = map[string]bool{}
+	}
+	frame.injected[token] = true
+}
+
+func (v *propertiesValidator) injectDefaults(x interface{}, ctx *Context) {
+	y, ok := x.(map[string]interface{})
+	if !ok || y == nil {
+		return
+	}
+
+	for k, schema := range v.properties {
+		if _, found := y[k]; found {
+			continue
+		}
+		if ctx.injectingDefault(schema) {
+			continue
+		}
+		if d, found := defaultValue(schema); found {
+			ctx.setProperty(k, deepCopy(d))
+			ctx.markInjected("/" + escapeJSONPointer(k))
+		}
+	}
+}
+
+func (v *itemsValidator) injectDefaults(x interface{}, ctx *Context) {
+	y, ok := x.([]interface{})
+	if !ok || y == nil || len(y) >= len(v.items) {
+		return
+	}
+
+	n := len(y)
+	y = y[:n:n]
+	for _, schema := range v.items[n:] {
+		if ctx.injectingDefault(schema) {
+			break
+		}
+		d, found := defaultValue(schema)
+		if !found {
+			break
+		}
+		ctx.markInjected("/" + strconv.Itoa(len(y)))
+		y = append(y, deepCopy(d))
+	}
+
+	if len(y) > n {
+		ctx.UpdateValue(y)
+	}
+}
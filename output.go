@@ -0,0 +1,265 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutputFormat selects the structure of the result of Schema.ValidateOutput
+// (see the "Output Formatting" section of JSON Schema 2019-09).
+type OutputFormat int
+
+const (
+	// FlagOutput only reports whether the instance is valid.
+	FlagOutput OutputFormat = iota
+
+	// BasicOutput reports all errors as a flat list.
+	BasicOutput
+
+	// DetailedOutput reports the errors in a hierarchy which follows the
+	// structure of the schema.
+	DetailedOutput
+
+	// VerboseOutput reports the result of every schema and keyword which
+	// was evaluated (including the valid ones).
+	VerboseOutput
+)
+
+// OutputUnit is the result of evaluating a schema or keyword against a
+// location in the instance. Nested units are listed in Errors when the unit
+// is invalid and in Annotations when it is valid.
+type OutputUnit struct {
+	Valid                   bool          `json:"valid"`
+	KeywordLocation         string        `json:"keywordLocation"`
+	AbsoluteKeywordLocation string        `json:"absoluteKeywordLocation,omitempty"`
+	InstanceLocation        string        `json:"instanceLocation"`
+	Error                   string        `json:"error,omitempty"`
+	Errors                  []*OutputUnit `json:"errors,omitempty"`
+	Annotations             []*OutputUnit `json:"annotations,omitempty"`
+}
+
+// ValidateOutput validates v and returns the result in the requested format.
+func (s *Schema) ValidateOutput(v interface{}, format OutputFormat, options ...ValidateOption) *OutputUnit {
+	if format == FlagOutput {
+		// the evaluation isn't recorded when only the validity is reported
+		err := s.Validate(v, options...)
+		return &OutputUnit{Valid: err == nil}
+	}
+
+	ctx := newContext(options...)
+	ctx.output = true
+	ctx.ValidateValueWith(v, s)
+
+	result := ctx.result
+	switch format {
+	case BasicOutput:
+		return result.basic()
+	case DetailedOutput:
+		return result.detailed()
+	default:
+		return result
+	}
+}
+
+func (u *OutputUnit) add(units ...*OutputUnit) {
+	if u.Valid {
+		u.Annotations = append(u.Annotations, units...)
+	} else {
+		u.Errors = append(u.Errors, units...)
+	}
+}
+
+// basic returns a unit with all the errors of u (and of its nested units)
+// as a flat list.
+func (u *OutputUnit) basic() *OutputUnit {
+	result := &OutputUnit{
+		Valid:                   u.Valid,
+		KeywordLocation:         u.KeywordLocation,
+		AbsoluteKeywordLocation: u.AbsoluteKeywordLocation,
+		InstanceLocation:        u.InstanceLocation,
+	}
+
+	var collect func(units []*OutputUnit)
+	collect = func(units []*OutputUnit) {
+		for _, unit := range units {
+			if unit.Valid {
+				continue
+			}
+			if unit.Error != "" {
+				result.Errors = append(result.Errors, &OutputUnit{
+					KeywordLocation:         unit.KeywordLocation,
+					AbsoluteKeywordLocation: unit.AbsoluteKeywordLocation,
+					InstanceLocation:        unit.InstanceLocation,
+					Error:                   unit.Error,
+				})
+			}
+			collect(unit.Errors)
+		}
+	}
+	collect(u.Errors)
+
+	return result
+}
+
+// detailed returns u without the valid units. Schema units with a single
+// nested unit are replaced by that unit.
+func (u *OutputUnit) detailed() *OutputUnit {
+	result := &OutputUnit{
+		Valid:                   u.Valid,
+		KeywordLocation:         u.KeywordLocation,
+		AbsoluteKeywordLocation: u.AbsoluteKeywordLocation,
+		InstanceLocation:        u.InstanceLocation,
+		Error:                   u.Error,
+	}
+
+	for _, unit := range u.Errors {
+		if unit.Valid {
+			continue
+		}
+
+		unit = unit.detailed()
+		for unit.Error == "" && len(unit.Errors) == 1 {
+			unit = unit.Errors[0]
+		}
+		result.Errors = append(result.Errors, unit)
+	}
+
+	return result
+}
+
+// newSchemaUnit returns the unit for the schema of frame l.
+func (c *Context) newSchemaUnit(l int) *OutputUnit {
+	var (
+		frame            = &c.stack[l]
+		keywordLocation  = c.relativeKeywordLocation(l)
+		instanceLocation = frame.instanceToken()
+	)
+
+	if l > 0 {
+		parent := &c.stack[l-1]
+		keywordLocation = parent.unit.KeywordLocation + keywordLocation
+		instanceLocation = parent.unit.InstanceLocation + instanceLocation
+	}
+
+	return &OutputUnit{
+		Valid:                   true,
+		KeywordLocation:         keywordLocation,
+		AbsoluteKeywordLocation: absoluteKeywordLocation(frame.schema, ""),
+		InstanceLocation:        instanceLocation,
+	}
+}
+
+// recordKeyword records the result of the validator which ran last on frame
+// l. n is the number of errors of the frame before the validator ran.
+func (c *Context) recordKeyword(l int, n int) {
+	var (
+		frame    = &c.stack[l]
+		errors   = frame.errors[n:]
+		children = frame.children
+	)
+
+	frame.children = nil
+
+	newUnit := func(keyword string, message string) *OutputUnit {
+		return &OutputUnit{
+			Valid:                   message == "",
+			KeywordLocation:         joinKeywordLocation(frame.unit.KeywordLocation, keyword),
+			AbsoluteKeywordLocation: absoluteKeywordLocation(frame.schema, keyword),
+			InstanceLocation:        frame.unit.InstanceLocation,
+			Error:                   message,
+		}
+	}
+
+	switch {
+	case len(errors) == 0:
+		unit := newUnit(frame.keyword, "")
+		unit.add(children...)
+		frame.units = append(frame.units, unit)
+
+	case len(children) > 0:
+		messages := make([]string, len(errors))
+		for i, err := range errors {
+			messages[i] = outputMessage(err)
+		}
+		unit := newUnit(errorKeyword(frame.schema, errors[0], frame.keyword), strings.Join(messages, "; "))
+		unit.add(children...)
+		frame.units = append(frame.units, unit)
+
+	default:
+		for _, err := range errors {
+			frame.units = append(frame.units, newUnit(errorKeyword(frame.schema, err, frame.keyword), outputMessage(err)))
+		}
+	}
+}
+
+// recordSchema completes the unit of frame l and adds it to its parent
+// frame (or makes it the result).
+func (c *Context) recordSchema(l int) {
+	frame := &c.stack[l]
+
+	unit := frame.unit
+	unit.Valid = len(frame.errors) == 0
+	unit.add(frame.units...)
+
+	if l == 0 {
+		c.result = unit
+		return
+	}
+
+	parent := &c.stack[l-1]
+	parent.children = append(parent.children, unit)
+}
+
+// absoluteKeywordLocation returns the location of keyword in schema as an
+// absolute URI. It is empty for schemas which don't have an absolute URI.
+func absoluteKeywordLocation(schema *Schema, keyword string) string {
+	if schema.Id == nil || !schema.Id.IsAbs() {
+		return ""
+	}
+
+	u := *schema.Id
+	if keyword != "" {
+		u.Fragment += "/" + escapeJSONPointer(keyword)
+	}
+	return normalizeRef(u.String())
+}
+
+// errorKeyword returns the keyword of schema which caused err. It defaults
+// to the keyword of the validator which reported err.
+func errorKeyword(schema *Schema, err error, keyword string) string {
+	if e, ok := err.(interface {
+		Keyword() string
+	}); ok {
+		if _, found := schema.Definition[e.Keyword()]; found {
+			return e.Keyword()
+		}
+	}
+	return keyword
+}
+
+// outputMessage returns the message of err for an OutputUnit. The errors
+// of the nested units are not included.
+func outputMessage(err error) string {
+	switch e := err.(type) {
+	case *ErrInvalidInstance:
+		return "value is not valid for the referenced schema"
+	case *ErrInvalidProperty:
+		return fmt.Sprintf("invalid property %q", e.Property)
+	case *ErrInvalidPropertyName:
+		return fmt.Sprintf("invalid property name %q", e.Property)
+	case *ErrInvalidItem:
+		return fmt.Sprintf("invalid item at %d", e.Index)
+	case *ErrNotAllOf:
+		return "value must be valid for all of the schemas"
+	case *ErrNotAnyOf:
+		return "value must be valid for at least one of the schemas"
+	case *ErrNotOneOf:
+		return "value must be valid for exactly one of the schemas"
+	case *ErrNotThen:
+		return "value matches 'if' but not 'then'"
+	case *ErrNotElse:
+		return "value does not match 'if' nor 'else'"
+	default:
+		return err.Error()
+	}
+}
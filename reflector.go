@@ -0,0 +1,477 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reflector generates schema documents from Go types.
+//
+// Structs become objects with a property for each field (named by its
+// `json` tag). The fields without `omitempty` are required. Pointers are
+// nullable. Named struct types are defined in `definitions` (or `$defs`)
+// so recursive types can refer to themselves.
+//
+// The `jsonschema` tag adds keywords to the schema of a field, like
+// `jsonschema:"minimum=1,maximum=10"`. Commas in values are escaped with a
+// backslash. `enum` may be repeated and `required` (or `required=false`)
+// overrides the `omitempty` rule.
+type Reflector struct {
+	// Dialect is the `$schema` of the documents. It defaults to draft-07.
+	Dialect string
+}
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	jsonNumberType     = reflect.TypeOf(json.Number(""))
+	jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// ReflectSchema builds the schema of the type of v with the default
+// Reflector.
+func (e *Env) ReflectSchema(id string, v interface{}) (*Schema, error) {
+	data, err := (&Reflector{}).Reflect(v)
+	if err != nil {
+		return nil, err
+	}
+	return e.BuildSchema(id, data)
+}
+
+// Reflect returns the schema document of the type of v.
+func (r *Reflector) Reflect(v interface{}) ([]byte, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("cannot reflect the schema of nil")
+	}
+	return r.ReflectType(t)
+}
+
+// ReflectType returns the schema document of t.
+func (r *Reflector) ReflectType(t reflect.Type) ([]byte, error) {
+	doc, err := r.reflectDocument(t)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func (r *Reflector) reflectDocument(t reflect.Type) (map[string]interface{}, error) {
+	dialect := r.Dialect
+	if dialect == "" {
+		dialect = "http://json-schema.org/draft-07/schema#"
+	}
+
+	x := &reflection{
+		root:        t,
+		names:       map[reflect.Type]string{},
+		definitions: map[string]interface{}{},
+		defsKeyword: "definitions",
+		nullable:    normalizeRef(dialect) == normalizeRef(OpenAPI3Dialect),
+	}
+	if strings.HasPrefix(dialect, "https://json-schema.org/draft/") {
+		x.defsKeyword = "$defs"
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		x.root = t
+	}
+
+	doc, err := x.inline(t)
+	if err != nil {
+		return nil, err
+	}
+
+	doc["$schema"] = dialect
+	if len(x.definitions) > 0 {
+		doc[x.defsKeyword] = x.definitions
+	}
+	return doc, nil
+}
+
+// reflection holds the state of a Reflector while it generates a document.
+type reflection struct {
+	root        reflect.Type
+	names       map[reflect.Type]string
+	definitions map[string]interface{}
+	defsKeyword string
+
+	// nullable is set for the OpenAPI 3.0 dialect which has the `nullable`
+	// keyword instead of the `null` type.
+	nullable bool
+}
+
+// schemaOf returns the schema of t. Named struct types are replaced by a
+// reference to their definition.
+func (x *reflection) schemaOf(t reflect.Type) (map[string]interface{}, error) {
+	if t.Kind() == reflect.Struct && t.Name() != "" && !isSpecialType(t) {
+		if t == x.root {
+			return map[string]interface{}{"$ref": "#"}, nil
+		}
+
+		name, found := x.names[t]
+		if !found {
+			name = x.definitionName(t)
+			x.names[t] = name
+			x.definitions[name] = true // placeholder for recursive types
+
+			def, err := x.inline(t)
+			if err != nil {
+				return nil, err
+			}
+			x.definitions[name] = def
+		}
+
+		return map[string]interface{}{"$ref": "#/" + x.defsKeyword + "/" + escapeJSONPointer(name)}, nil
+	}
+
+	return x.inline(t)
+}
+
+// inline returns the schema of t (without a reference for named structs).
+func (x *reflection) inline(t reflect.Type) (map[string]interface{}, error) {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t == jsonNumberType:
+		return map[string]interface{}{"type": "number"}, nil
+	case t == jsonRawMessageType:
+		return map[string]interface{}{}, nil
+	case t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface:
+		if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+			return map[string]interface{}{}, nil
+		}
+		if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+			return map[string]interface{}{"type": "string"}, nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "minimum": 0}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+
+	case reflect.Ptr:
+		s, err := x.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return x.makeNullable(s), nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+
+		items, err := x.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]interface{}{"type": "array", "items": items}
+		if t.Kind() == reflect.Array {
+			s["minItems"] = t.Len()
+			s["maxItems"] = t.Len()
+		}
+		return s, nil
+
+	case reflect.Map:
+		values, err := x.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]interface{}{"type": "object", "additionalProperties": values}
+
+		switch k := t.Key(); {
+		case k.Kind() == reflect.String, k.Implements(textMarshalerType):
+		case k.Kind() >= reflect.Int && k.Kind() <= reflect.Uintptr:
+			s["propertyNames"] = map[string]interface{}{"pattern": "^-?[0-9]+$"}
+		default:
+			return nil, fmt.Errorf("unsupported map key type: %s", k)
+		}
+		return s, nil
+
+	case reflect.Struct:
+		return x.structSchema(t)
+
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", t)
+	}
+}
+
+func (x *reflection) structSchema(t reflect.Type) (map[string]interface{}, error) {
+	var (
+		properties = map[string]interface{}{}
+		required   []string
+	)
+
+	for _, f := range structFields(t) {
+		s, err := x.schemaOf(f.typ)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", t, f.name, err)
+		}
+		if f.quoted {
+			switch f.typ.Kind() {
+			case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+				reflect.Float32, reflect.Float64, reflect.String:
+				s = map[string]interface{}{"type": "string"}
+			}
+		}
+
+		isRequired := !f.omitEmpty
+		if tag, found := f.tag.Lookup("jsonschema"); found {
+			s, err = x.applyTag(s, tag, &isRequired)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %s", t, f.name, err)
+			}
+		}
+
+		properties[f.name] = s
+		if isRequired {
+			required = append(required, f.name)
+		}
+	}
+
+	s := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s, nil
+}
+
+// makeNullable returns a schema which also allows null.
+func (x *reflection) makeNullable(s map[string]interface{}) map[string]interface{} {
+	if len(s) == 0 {
+		return s
+	}
+
+	if x.nullable {
+		if _, isRef := s["$ref"]; isRef {
+			s = map[string]interface{}{"allOf": []interface{}{s}}
+		}
+		s["nullable"] = true
+		return s
+	}
+
+	if t, ok := s["type"].(string); ok {
+		s["type"] = []interface{}{t, "null"}
+		return s
+	}
+
+	return map[string]interface{}{
+		"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}},
+	}
+}
+
+// definitionName returns a unique name for the definition of t.
+func (x *reflection) definitionName(t reflect.Type) string {
+	name := reflectorNameRegexp.ReplaceAllString(t.Name(), "_")
+	if _, taken := x.definitions[name]; !taken {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	base := reflectorNameRegexp.ReplaceAllString(pkg, "_") + "." + name
+	name = base
+	for i := 2; ; i++ {
+		if _, taken := x.definitions[name]; !taken {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
+
+var reflectorNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// isSpecialType returns true for the struct types with a fixed schema.
+func isSpecialType(t reflect.Type) bool {
+	return t == timeType ||
+		t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+// applyTag adds the keywords of a `jsonschema` tag to s.
+func (x *reflection) applyTag(s map[string]interface{}, tag string, required *bool) (map[string]interface{}, error) {
+	entries := splitTag(tag)
+	if len(entries) == 0 {
+		return s, nil
+	}
+
+	// keywords next to a `$ref` are ignored by the older drafts
+	if _, isRef := s["$ref"]; isRef {
+		s = map[string]interface{}{"allOf": []interface{}{s}}
+	}
+
+	typ := schemaTagType(s)
+
+	for _, entry := range entries {
+		key, value := entry, ""
+		hasValue := false
+		if i := strings.IndexByte(entry, '='); i >= 0 {
+			key, value, hasValue = entry[:i], entry[i+1:], true
+		}
+
+		switch key {
+		case "required":
+			b, err := parseTagBool(value, hasValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q in jsonschema tag: %s", key, err)
+			}
+			*required = b
+
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("invalid %q in jsonschema tag: %q", key, value)
+			}
+			s[key] = json.Number(value)
+
+		case "minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid %q in jsonschema tag: %q", key, value)
+			}
+			s[key] = n
+
+		case "pattern":
+			if _, err := regexp.Compile(value); err != nil {
+				return nil, fmt.Errorf("invalid %q in jsonschema tag: %s", key, err)
+			}
+			s[key] = value
+
+		case "format", "title", "description", "contentEncoding", "contentMediaType":
+			s[key] = value
+
+		case "uniqueItems", "readOnly", "writeOnly", "deprecated":
+			b, err := parseTagBool(value, hasValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q in jsonschema tag: %s", key, err)
+			}
+			s[key] = b
+
+		case "enum":
+			v, err := parseTagValue(typ, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q in jsonschema tag: %s", key, err)
+			}
+			enum, _ := s[key].([]interface{})
+			s[key] = append(enum, v)
+
+		case "default", "const":
+			v, err := parseTagValue(typ, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q in jsonschema tag: %s", key, err)
+			}
+			s[key] = v
+
+		default:
+			return nil, fmt.Errorf("unknown keyword in jsonschema tag: %q", key)
+		}
+	}
+
+	return s, nil
+}
+
+// splitTag splits a `jsonschema` tag at the commas which are not escaped.
+func splitTag(tag string) []string {
+	var (
+		entries []string
+		entry   strings.Builder
+	)
+
+	for i := 0; i < len(tag); i++ {
+		switch c := tag[i]; {
+		case c == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			entry.WriteByte(',')
+			i++
+		case c == ',':
+			entries = append(entries, entry.String())
+			entry.Reset()
+		default:
+			entry.WriteByte(c)
+		}
+	}
+	entries = append(entries, entry.String())
+
+	result := entries[:0]
+	for _, e := range entries {
+		if e != "" {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// schemaTagType returns the (non-null) type of s which determines how the
+// values of `enum`, `const` and `default` are parsed.
+func schemaTagType(s map[string]interface{}) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, u := range t {
+			if u != "null" {
+				return u.(string)
+			}
+		}
+	}
+	return ""
+}
+
+func parseTagBool(value string, hasValue bool) (bool, error) {
+	if !hasValue {
+		return true, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func parseTagValue(typ string, value string) (interface{}, error) {
+	switch typ {
+	case "string":
+		return value, nil
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid integer %q", value)
+		}
+		return json.Number(value), nil
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("invalid number %q", value)
+		}
+		return json.Number(value), nil
+	case "boolean":
+		return strconv.ParseBool(value)
+	default:
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return value, nil
+		}
+		return v, nil
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"net/url"
 )
 
+// Schema is a built schema. Schemas are safe for concurrent use by multiple
+// goroutines (the validation never modifies them) but the Env which builds
+// them isn't.
 type Schema struct {
 	Id         *url.URL
 	Ref        *url.URL
@@ -13,6 +16,32 @@ type Schema struct {
 	Validators []Validator
 	Definition map[string]interface{}
 	Subschemas map[string]*Schema
+
+	// Bool is set for the boolean schemas `true` and `false`.
+	Bool *bool
+
+	// parent is the schema in which the schema is defined and pointer is
+	// its location in the parent (like `/properties/foo`). References
+	// created by the Builder have no pointer.
+	parent  *Schema
+	pointer string
+
+	// children are the schemas defined in the schema by their pointer.
+	children map[string]*Schema
+
+	// keywords has the keyword (in the schema) of each of the Validators.
+	keywords []string
+
+	// resource is the schema which defines the base URI of this schema.
+	resource *Schema
+
+	// dynamicAnchors are the `$dynamicAnchor` (and `$recursiveAnchor`)
+	// targets defined in the schema resource.
+	dynamicAnchors map[string]*Schema
+
+	// tracksEvaluation is true when one of the validators depends on the
+	// properties and items which were evaluated by the other validators.
+	tracksEvaluation bool
 }
 
 type Validator interface {
@@ -24,12 +53,49 @@ type FormatValidator interface {
 	IsValid(interface{}) bool
 }
 
-func (s *Schema) Validate(v interface{}) error {
-	_, err := newContext().ValidateValueWith(v, s)
+// falseValidator rejects all instances; it is the only validator of the
+// boolean schema `false`.
+type falseValidator struct{}
+
+func (v *falseValidator) Setup(b Builder) error {
+	return nil
+}
+
+func (v *falseValidator) Validate(x interface{}, ctx *Context) {
+	ctx.Report(&ErrFalseSchema{})
+}
+
+func (s *Schema) addDynamicAnchor(name string, target *Schema) {
+	if s.dynamicAnchors == nil {
+		s.dynamicAnchors = map[string]*Schema{}
+	}
+	s.dynamicAnchors[name] = target
+}
+
+func (s *Schema) addChild(pointer string, child *Schema) {
+	if s.children == nil {
+		s.children = map[string]*Schema{}
+	}
+	s.children[pointer] = child
+}
+
+// Validate validates v. v is a value decoded by encoding/json or any other
+// Go value (like a struct, which is validated through its `json` tags). The
+// value is never modified (use ValidateValue to get the normalized value).
+func (s *Schema) Validate(v interface{}, options ...ValidateOption) error {
+	_, err := s.ValidateValue(v, options...)
 	return err
 }
 
-func (s *Schema) ValidateData(d []byte) error {
+// ValidateValue validates v and returns it with the updates made during the
+// validation (like the numbers converted to int64 and float64 and the
+// defaults filled in by WithDefaults). The updated objects and arrays are
+// copies; v itself is never modified.
+func (s *Schema) ValidateValue(v interface{}, options ...ValidateOption) (interface{}, error) {
+	return newContext(options...).ValidateValueWith(v, s)
+}
+
+func (s *Schema) ValidateData(d []byte, options ...ValidateOption) error {
 	var (
 		v interface{}
 	)
@@ -41,5 +107,14 @@ func (s *Schema) ValidateData(d []byte) error {
 		return err
 	}
 
-	return s.Validate(v)
+	_, err = validateInPlace(s, v, options)
+	return err
+}
+
+// validateInPlace validates v, a value decoded from JSON which is not
+// shared, so it is updated in place.
+func validateInPlace(s *Schema, v interface{}, options []ValidateOption) (interface{}, error) {
+	ctx := newContext(options...)
+	ctx.inPlace = true
+	return ctx.ValidateValueWith(v, s)
 }
@@ -3,12 +3,41 @@ package jsonschema
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"os/exec"
 	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
+var testSuiteDialects = map[string]string{
+	"openapi3":     OpenAPI3Dialect,
+	"draft3":       "http://json-schema.org/draft-03/schema#",
+	"draft4":       "http://json-schema.org/draft-04/schema#",
+	"draft6":       "http://json-schema.org/draft-06/schema#",
+	"draft7":       "http://json-schema.org/draft-07/schema#",
+	"draft2019-09": "https://json-schema.org/draft/2019-09/schema#",
+	"draft2020-12": "https://json-schema.org/draft/2020-12/schema#",
+}
+
 func TestCoreIdenitySchema(t *testing.T) {
 	var def map[string]interface{}
 	load_test_json("core.json", &def)
@@ -24,6 +53,35 @@ func TestCoreIdenitySchema(t *testing.T) {
 	}
 }
 
+func TestDraft3(t *testing.T) {
+	run_test_suite(t, "draft3/additionalItems.json")
+	run_test_suite(t, "draft3/additionalProperties.json")
+	run_test_suite(t, "draft3/dependencies.json")
+	run_test_suite(t, "draft3/disallow.json")
+	run_test_suite(t, "draft3/divisibleBy.json")
+	run_test_suite(t, "draft3/enum.json")
+	run_test_suite(t, "draft3/extends.json")
+	run_test_suite(t, "draft3/items.json")
+	run_test_suite(t, "draft3/maxItems.json")
+	run_test_suite(t, "draft3/maxLength.json")
+	run_test_suite(t, "draft3/maximum.json")
+	run_test_suite(t, "draft3/minItems.json")
+	run_test_suite(t, "draft3/minLength.json")
+	run_test_suite(t, "draft3/minimum.json")
+	run_test_suite(t, "draft3/pattern.json")
+	run_test_suite(t, "draft3/patternProperties.json")
+	run_test_suite(t, "draft3/properties.json")
+	run_test_suite(t, "draft3/ref.json")
+	run_test_suite(t, "draft3/required.json")
+	run_test_suite(t, "draft3/type.json")
+	run_test_suite(t, "draft3/uniqueItems.json")
+}
+
+func TestDraft3Optional(t *testing.T) {
+	run_test_suite(t, "draft3/optional/format.json")
+	run_test_suite(t, "draft3/optional/zeroTerminatedFloats.json")
+}
+
 func TestDraft4(t *testing.T) {
 	run_test_suite(t, "draft4/additionalItems.json")
 	run_test_suite(t, "draft4/additionalProperties.json")
@@ -60,45 +118,2700 @@ func TestDraft4Optional(t *testing.T) {
 	run_test_suite(t, "draft4/optional/zeroTerminatedFloats.json")
 }
 
-func load_test_data(path string) []byte {
-	data, err := ioutil.ReadFile("testdata/" + path)
+func TestDraft6(t *testing.T) {
+	run_test_suite(t, "draft6/additionalItems.json")
+	run_test_suite(t, "draft6/additionalProperties.json")
+	run_test_suite(t, "draft6/allOf.json")
+	run_test_suite(t, "draft6/anyOf.json")
+	run_test_suite(t, "draft6/boolean_schema.json")
+	run_test_suite(t, "draft6/const.json")
+	run_test_suite(t, "draft6/contains.json")
+	run_test_suite(t, "draft6/definitions.json")
+	run_test_suite(t, "draft6/dependencies.json")
+	run_test_suite(t, "draft6/enum.json")
+	run_test_suite(t, "draft6/exclusiveMaximum.json")
+	run_test_suite(t, "draft6/exclusiveMinimum.json")
+	run_test_suite(t, "draft6/items.json")
+	run_test_suite(t, "draft6/maxItems.json")
+	run_test_suite(t, "draft6/maxLength.json")
+	run_test_suite(t, "draft6/maxProperties.json")
+	run_test_suite(t, "draft6/maximum.json")
+	run_test_suite(t, "draft6/minItems.json")
+	run_test_suite(t, "draft6/minLength.json")
+	run_test_suite(t, "draft6/minProperties.json")
+	run_test_suite(t, "draft6/minimum.json")
+	run_test_suite(t, "draft6/multipleOf.json")
+	run_test_suite(t, "draft6/not.json")
+	run_test_suite(t, "draft6/oneOf.json")
+	run_test_suite(t, "draft6/pattern.json")
+	run_test_suite(t, "draft6/patternProperties.json")
+	run_test_suite(t, "draft6/properties.json")
+	run_test_suite(t, "draft6/propertyNames.json")
+	run_test_suite(t, "draft6/ref.json")
+	run_test_suite(t, "draft6/refRemote.json")
+	run_test_suite(t, "draft6/required.json")
+	run_test_suite(t, "draft6/type.json")
+	run_test_suite(t, "draft6/uniqueItems.json")
+}
+
+func TestDraft6Optional(t *testing.T) {
+	// run_test_suite(t, "draft6/optional/bignum.json")
+	run_test_suite(t, "draft6/optional/format.json")
+	run_test_suite(t, "draft6/optional/zeroTerminatedFloats.json")
+}
+
+func TestDraft7(t *testing.T) {
+	run_test_suite(t, "draft7/additionalItems.json")
+	run_test_suite(t, "draft7/additionalProperties.json")
+	run_test_suite(t, "draft7/allOf.json")
+	run_test_suite(t, "draft7/anyOf.json")
+	run_test_suite(t, "draft7/boolean_schema.json")
+	run_test_suite(t, "draft7/const.json")
+	run_test_suite(t, "draft7/contains.json")
+	run_test_suite(t, "draft7/definitions.json")
+	run_test_suite(t, "draft7/dependencies.json")
+	run_test_suite(t, "draft7/enum.json")
+	run_test_suite(t, "draft7/exclusiveMaximum.json")
+	run_test_suite(t, "draft7/exclusiveMinimum.json")
+	run_test_suite(t, "draft7/if-then-else.json")
+	run_test_suite(t, "draft7/items.json")
+	run_test_suite(t, "draft7/maxItems.json")
+	run_test_suite(t, "draft7/maxLength.json")
+	run_test_suite(t, "draft7/maxProperties.json")
+	run_test_suite(t, "draft7/maximum.json")
+	run_test_suite(t, "draft7/minItems.json")
+	run_test_suite(t, "draft7/minLength.json")
+	run_test_suite(t, "draft7/minProperties.json")
+	run_test_suite(t, "draft7/minimum.json")
+	run_test_suite(t, "draft7/multipleOf.json")
+	run_test_suite(t, "draft7/not.json")
+	run_test_suite(t, "draft7/oneOf.json")
+	run_test_suite(t, "draft7/pattern.json")
+	run_test_suite(t, "draft7/patternProperties.json")
+	run_test_suite(t, "draft7/properties.json")
+	run_test_suite(t, "draft7/propertyNames.json")
+	run_test_suite(t, "draft7/ref.json")
+	run_test_suite(t, "draft7/refRemote.json")
+	run_test_suite(t, "draft7/required.json")
+	run_test_suite(t, "draft7/type.json")
+	run_test_suite(t, "draft7/uniqueItems.json")
+}
+
+func TestDraft7Optional(t *testing.T) {
+	// run_test_suite(t, "draft7/optional/bignum.json")
+	run_test_suite(t, "draft7/optional/format.json")
+	run_test_suite(t, "draft7/optional/zeroTerminatedFloats.json")
+}
+
+func TestDraft2019_09(t *testing.T) {
+	run_test_suite(t, "draft2019-09/additionalItems.json")
+	run_test_suite(t, "draft2019-09/additionalProperties.json")
+	run_test_suite(t, "draft2019-09/allOf.json")
+	run_test_suite(t, "draft2019-09/anchor.json")
+	run_test_suite(t, "draft2019-09/anyOf.json")
+	run_test_suite(t, "draft2019-09/boolean_schema.json")
+	run_test_suite(t, "draft2019-09/const.json")
+	run_test_suite(t, "draft2019-09/contains.json")
+	run_test_suite(t, "draft2019-09/defs.json")
+	run_test_suite(t, "draft2019-09/dependentRequired.json")
+	run_test_suite(t, "draft2019-09/dependentSchemas.json")
+	run_test_suite(t, "draft2019-09/enum.json")
+	run_test_suite(t, "draft2019-09/exclusiveMaximum.json")
+	run_test_suite(t, "draft2019-09/exclusiveMinimum.json")
+	run_test_suite(t, "draft2019-09/format.json")
+	run_test_suite(t, "draft2019-09/if-then-else.json")
+	run_test_suite(t, "draft2019-09/items.json")
+	run_test_suite(t, "draft2019-09/maxContains.json")
+	run_test_suite(t, "draft2019-09/maxItems.json")
+	run_test_suite(t, "draft2019-09/maxLength.json")
+	run_test_suite(t, "draft2019-09/maxProperties.json")
+	run_test_suite(t, "draft2019-09/maximum.json")
+	run_test_suite(t, "draft2019-09/minContains.json")
+	run_test_suite(t, "draft2019-09/minItems.json")
+	run_test_suite(t, "draft2019-09/minLength.json")
+	run_test_suite(t, "draft2019-09/minProperties.json")
+	run_test_suite(t, "draft2019-09/minimum.json")
+	run_test_suite(t, "draft2019-09/multipleOf.json")
+	run_test_suite(t, "draft2019-09/not.json")
+	run_test_suite(t, "draft2019-09/oneOf.json")
+	run_test_suite(t, "draft2019-09/pattern.json")
+	run_test_suite(t, "draft2019-09/patternProperties.json")
+	run_test_suite(t, "draft2019-09/properties.json")
+	run_test_suite(t, "draft2019-09/propertyNames.json")
+	run_test_suite(t, "draft2019-09/recursiveRef.json")
+	run_test_suite(t, "draft2019-09/ref.json")
+	run_test_suite(t, "draft2019-09/refRemote.json")
+	run_test_suite(t, "draft2019-09/required.json")
+	run_test_suite(t, "draft2019-09/type.json")
+	run_test_suite(t, "draft2019-09/unevaluatedItems.json")
+	run_test_suite(t, "draft2019-09/unevaluatedProperties.json")
+	run_test_suite(t, "draft2019-09/uniqueItems.json")
+}
+
+func TestDraft2019_09Optional(t *testing.T) {
+	// run_test_suite(t, "draft2019-09/optional/bignum.json")
+	run_test_suite(t, "draft2019-09/optional/format.json", WithFormatAssertion())
+	run_test_suite(t, "draft2019-09/optional/zeroTerminatedFloats.json")
+}
+
+func TestDraft2020_12(t *testing.T) {
+	run_test_suite(t, "draft2020-12/additionalProperties.json")
+	run_test_suite(t, "draft2020-12/allOf.json")
+	run_test_suite(t, "draft2020-12/anchor.json")
+	run_test_suite(t, "draft2020-12/anyOf.json")
+	run_test_suite(t, "draft2020-12/boolean_schema.json")
+	run_test_suite(t, "draft2020-12/const.json")
+	run_test_suite(t, "draft2020-12/contains.json")
+	run_test_suite(t, "draft2020-12/defs.json")
+	run_test_suite(t, "draft2020-12/dependentRequired.json")
+	run_test_suite(t, "draft2020-12/dependentSchemas.json")
+	run_test_suite(t, "draft2020-12/dynamicRef.json")
+	run_test_suite(t, "draft2020-12/enum.json")
+	run_test_suite(t, "draft2020-12/exclusiveMaximum.json")
+	run_test_suite(t, "draft2020-12/exclusiveMinimum.json")
+	run_test_suite(t, "draft2020-12/format.json")
+	run_test_suite(t, "draft2020-12/if-then-else.json")
+	run_test_suite(t, "draft2020-12/items.json")
+	run_test_suite(t, "draft2020-12/maxContains.json")
+	run_test_suite(t, "draft2020-12/maxItems.json")
+	run_test_suite(t, "draft2020-12/maxLength.json")
+	run_test_suite(t, "draft2020-12/maxProperties.json")
+	run_test_suite(t, "draft2020-12/maximum.json")
+	run_test_suite(t, "draft2020-12/minContains.json")
+	run_test_suite(t, "draft2020-12/minItems.json")
+	run_test_suite(t, "draft2020-12/minLength.json")
+	run_test_suite(t, "draft2020-12/minProperties.json")
+	run_test_suite(t, "draft2020-12/minimum.json")
+	run_test_suite(t, "draft2020-12/multipleOf.json")
+	run_test_suite(t, "draft2020-12/not.json")
+	run_test_suite(t, "draft2020-12/oneOf.json")
+	run_test_suite(t, "draft2020-12/pattern.json")
+	run_test_suite(t, "draft2020-12/patternProperties.json")
+	run_test_suite(t, "draft2020-12/prefixItems.json")
+	run_test_suite(t, "draft2020-12/properties.json")
+	run_test_suite(t, "draft2020-12/propertyNames.json")
+	run_test_suite(t, "draft2020-12/ref.json")
+	run_test_suite(t, "draft2020-12/refRemote.json")
+	run_test_suite(t, "draft2020-12/required.json")
+	run_test_suite(t, "draft2020-12/type.json")
+	run_test_suite(t, "draft2020-12/unevaluatedItems.json")
+	run_test_suite(t, "draft2020-12/unevaluatedProperties.json")
+	run_test_suite(t, "draft2020-12/uniqueItems.json")
+	run_test_suite(t, "draft2020-12/vocabulary.json")
+}
+
+func TestDraft2020_12Optional(t *testing.T) {
+	// run_test_suite(t, "draft2020-12/optional/bignum.json")
+	run_test_suite(t, "draft2020-12/optional/format.json", WithFormatAssertion())
+	run_test_suite(t, "draft2020-12/optional/zeroTerminatedFloats.json")
+}
+
+func TestOpenAPI3(t *testing.T) {
+	run_test_suite(t, "openapi3/annotations.json")
+	run_test_suite(t, "openapi3/anyOf.json")
+	run_test_suite(t, "openapi3/enum.json")
+	run_test_suite(t, "openapi3/format.json")
+	run_test_suite(t, "openapi3/maxItems.json")
+	run_test_suite(t, "openapi3/maxLength.json")
+	run_test_suite(t, "openapi3/maxProperties.json")
+	run_test_suite(t, "openapi3/maximum.json")
+	run_test_suite(t, "openapi3/minItems.json")
+	run_test_suite(t, "openapi3/minLength.json")
+	run_test_suite(t, "openapi3/minProperties.json")
+	run_test_suite(t, "openapi3/minimum.json")
+	run_test_suite(t, "openapi3/multipleOf.json")
+	run_test_suite(t, "openapi3/nullable.json")
+	run_test_suite(t, "openapi3/oneOf.json")
+	run_test_suite(t, "openapi3/pattern.json")
+	run_test_suite(t, "openapi3/ref.json")
+	run_test_suite(t, "openapi3/required.json")
+	run_test_suite(t, "openapi3/uniqueItems.json")
+}
+
+func TestOpenAPI3ForbiddenKeywords(t *testing.T) {
+	for _, def := range []string{
+		`{"type": ["string", "null"]}`,
+		`{"patternProperties": {"^a": {}}}`,
+		`{"items": [{"type": "string"}]}`,
+		`{"const": 1}`,
+	} {
+		_, err := OpenAPIEnv.BuildSchema("", []byte(def))
+		if err == nil {
+			t.Errorf("%s: expected an error but non were generated", def)
+		}
+	}
+}
+
+func TestBuildSchemaWithoutId(t *testing.T) {
+	for _, env := range []*Env{RootEnv, OpenAPIEnv} {
+		schema, err := env.Clone().BuildSchema("http://example.com/a.json", []byte(`{
+			"properties": {
+				"a": { "type": "string" },
+				"b": { "$ref": "#/properties/a" }
+			}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id := schema.Id.String(); id != "http://example.com/a.json" {
+			t.Errorf("expected the id to be the URI of the document but was %q", id)
+		}
+		if len(schema.Definition) != 1 {
+			t.Errorf("expected the definition to be unchanged but was %v", schema.Definition)
+		}
+		if err := schema.Validate(map[string]interface{}{"b": 1}); err == nil {
+			t.Errorf("expected an error for the referenced schema")
+		}
+	}
+}
+
+func TestLoadRemoteSchema(t *testing.T) {
+	transport := &mapTransport{documents: map[string]string{
+		"http://example.com/no-id.json": `{ "definitions": { "n": { "type": "integer" } } }`,
+		"http://example.com/other.json": `{ "$id": "http://example.com/moved.json", "type": "string" }`,
+	}}
+
+	env := RootEnv.Clone()
+	env.Transport = transport
+
+	for i := 0; i < 2; i++ {
+		schema, err := env.BuildSchema("", []byte(`{
+			"properties": {
+				"a": { "$ref": "http://example.com/no-id.json#/definitions/n" },
+				"b": { "$ref": "http://example.com/other.json" }
+			}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := schema.Validate(map[string]interface{}{"a": int64(1), "b": "x"}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		if err := schema.Validate(map[string]interface{}{"a": "x", "b": int64(1)}); err == nil {
+			t.Errorf("expected an error for the referenced schemas")
+		}
+	}
+
+	// documents without an id are registered with their URL
+	if n := transport.gets["http://example.com/no-id.json"]; n != 1 {
+		t.Errorf("expected the document without an id to be loaded once but was loaded %d times", n)
+	}
+}
+
+// mapTransport serves the documents in the map and counts the requests.
+type mapTransport struct {
+	documents map[string]string
+	gets      map[string]int
+}
+
+func (t *mapTransport) Get(rawurl string) ([]byte, error) {
+	if t.gets == nil {
+		t.gets = map[string]int{}
+	}
+	t.gets[rawurl]++
+
+	data, found := t.documents[rawurl]
+	if !found {
+		return nil, fmt.Errorf("not found: %s", rawurl)
+	}
+	return []byte(data), nil
+}
+
+func TestLoadOpenAPI(t *testing.T) {
+	for _, name := range []string{"petstore-2.0.json", "petstore-3.0.json", "petstore-3.1.json"} {
+		data, err := ioutil.ReadFile("testdata/openapi/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env := RootEnv.Clone()
+		doc, err := env.LoadOpenAPI("http://example.com/"+name, data)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		if doc.Schemas["Pet"] == nil {
+			t.Fatalf("%s: missing Pet schema", name)
+		}
+
+		op := doc.Operation("post", "/pets")
+		if op == nil || op.OperationID != "createPet" {
+			t.Fatalf("%s: missing createPet operation", name)
+		}
+
+		body := op.RequestBody["application/json"]
+		if body == nil {
+			t.Fatalf("%s: missing request body", name)
+		}
+
+		for data, valid := range map[string]bool{
+			`{"id": 1, "name": "Rex"}`:   true,
+			`{"id": 1}`:                  false,
+			`{"id": "1", "name": "Rex"}`: false,
+		} {
+			err = body.ValidateData([]byte(data))
+			if valid && err != nil {
+				t.Errorf("%s: %s: error: %s", name, data, err)
+			}
+			if !valid && err == nil {
+				t.Errorf("%s: %s: expected an error but non were generated", name, data)
+			}
+		}
+
+		ref := "http://example.com/" + name + "#/components/schemas/Pet"
+		if name == "petstore-2.0.json" {
+			ref = "http://example.com/" + name + "#/definitions/Pet"
+		}
+		schema, err := env.BuildSchema("", []byte(`{"$ref": "`+ref+`"}`))
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err = schema.ValidateData([]byte(`{"id": 1}`)); err == nil {
+			t.Errorf("%s: expected an error but non were generated", name)
+		}
+	}
+
+	data, err := ioutil.ReadFile("testdata/openapi/petstore-3.0.json")
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
+	}
+	doc, err := RootEnv.Clone().LoadOpenAPI("", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := doc.Operation("GET", "/pets/{petId}")
+	if op == nil || len(op.Parameters) != 1 || op.Parameters[0].Name != "petId" {
+		t.Fatalf("expected a single petId parameter")
+	}
+	if err = op.Parameters[0].Schema.Validate("1"); err == nil {
+		t.Errorf("expected the operation parameter to override the path parameter")
+	}
+
+	op = doc.Operation("GET", "/pets")
+	if err = op.Parameters[0].Schema.ValidateData([]byte(`101`)); err == nil {
+		t.Errorf("expected an error but non were generated")
+	}
+	if op.Responses["default"]["application/json"] == nil {
+		t.Errorf("missing default response")
 	}
-	return data
 }
 
-func load_test_json(path string, v interface{}) {
-	dec := json.NewDecoder(bytes.NewReader(load_test_data(path)))
-	dec.UseNumber()
-	err := dec.Decode(v)
+func TestValidateOutput(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/polygon",
+		"$defs": {
+			"point": {
+				"type": "object",
+				"properties": {
+					"x": { "type": "number" },
+					"y": { "type": "number" }
+				},
+				"required": ["x", "y"]
+			}
+		},
+		"type": "array",
+		"items": { "$ref": "#/$defs/point" },
+		"minItems": 2
+	}`))
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
+	}
+
+	var v interface{}
+	err = json.Unmarshal([]byte(`[{"x": 2.5, "y": 1.3}, {"x": "1"}]`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schema.ValidateOutput(v, FlagOutput).Valid {
+		t.Fatalf("expected the instance to be invalid")
+	}
+	flag := schema.ValidateOutput([]interface{}{}, FlagOutput, WithDefaults())
+	if !reflect.DeepEqual(flag, &OutputUnit{Valid: false}) {
+		t.Errorf("expected only the validity but was %#v", flag)
+	}
+
+	basic := schema.ValidateOutput(v, BasicOutput)
+	expected := map[string]string{
+		"/items/$ref/required":          "/1",
+		"/items/$ref/properties/x/type": "/1/x",
+	}
+	for _, unit := range basic.Errors {
+		if instanceLocation, found := expected[unit.KeywordLocation]; found {
+			if unit.InstanceLocation != instanceLocation {
+				t.Errorf("%s: expected instance location %q but was %q", unit.KeywordLocation, instanceLocation, unit.InstanceLocation)
+			}
+			if unit.Error == "" {
+				t.Errorf("%s: expected an error message", unit.KeywordLocation)
+			}
+			delete(expected, unit.KeywordLocation)
+		}
+	}
+	for keywordLocation := range expected {
+		t.Errorf("missing error for %s", keywordLocation)
+	}
+
+	detailed := schema.ValidateOutput(v, DetailedOutput)
+	if len(detailed.Errors) != 1 || detailed.Errors[0].KeywordLocation != "/items" {
+		t.Errorf("expected a single error for /items")
+	}
+
+	err = json.Unmarshal([]byte(`[{"x": 2.5, "y": 1.3}, {"x": 1, "y": 0}]`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verbose := schema.ValidateOutput(v, VerboseOutput)
+	if !verbose.Valid || len(verbose.Annotations) == 0 {
+		t.Errorf("expected the valid keywords in the verbose output")
+	}
+
+	data, err := json.Marshal(detailed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var unit OutputUnit
+	err = json.Unmarshal(data, &unit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unit.Valid || unit.Errors[0].AbsoluteKeywordLocation != "https://example.com/polygon#/items" {
+		t.Errorf("unexpected JSON output: %s", data)
 	}
 }
 
-func run_test_suite(t *testing.T, path string) {
-	t.Logf("- %s", path)
+func TestErrorLocation(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"properties": {
+			"orders": {
+				"type": "array",
+				"items": {
+					"properties": {
+						"lines": {
+							"type": "array",
+							"items": { "$ref": "#/definitions/line" }
+						}
+					}
+				}
+			}
+		},
+		"definitions": {
+			"line": {
+				"properties": { "sku": { "type": "string" } }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	var suite []struct {
-		Description string          `json:"description"`
-		SchemaDef   json.RawMessage `json:"schema"`
-		Tests       []struct {
-			Description string      `json:"description"`
-			Data        interface{} `json:"data"`
-			Valid       bool        `json:"valid"`
+	err = schema.ValidateData([]byte(`{"orders": [{}, {}, {}, {"lines": [{"sku": 5}]}]}`))
+	if err == nil {
+		t.Fatalf("expected an error but non were generated")
+	}
+
+	var leaf *ErrInvalidType
+	if !errors.As(err, &leaf) {
+		t.Fatalf("expected an error for the sku")
+	}
+
+	if leaf.Code() != CodeInvalidType || leaf.Keyword() != "type" {
+		t.Errorf("unexpected error %s (%s)", leaf.Code(), leaf.Keyword())
+	}
+	if v, ok := leaf.Value().(json.Number); !ok || v.String() != "5" {
+		t.Errorf("expected the value 5 but was %#v", leaf.Value())
+	}
+	if types, ok := leaf.Expected().([]PrimitiveType); !ok || len(types) != 1 || types[0] != StringType {
+		t.Errorf("expected the type string but was %#v", leaf.Expected())
+	}
+	if l := leaf.InstanceLocation(); l != "/orders/3/lines/0/sku" {
+		t.Errorf("expected instance location /orders/3/lines/0/sku but was %q", l)
+	}
+	if l := leaf.KeywordLocation(); l != "/properties/orders/items/properties/lines/items/$ref/properties/sku/type" {
+		t.Errorf("unexpected keyword location %q", l)
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	for dialect, tuple := range map[string]string{
+		"http://json-schema.org/draft-07/schema#":      "items",
+		"https://json-schema.org/draft/2020-12/schema": "prefixItems",
+	} {
+		schema, err := RootEnv.BuildSchema("", []byte(`{
+			"$schema": "`+dialect+`",
+			"properties": {
+				"port": { "type": "integer", "default": 8080 },
+				"tls": { "$ref": "#/definitions/tls" },
+				"hosts": {
+					"type": "array",
+					"`+tuple+`": [{ "type": "string" }, { "default": "localhost" }]
+				}
+			},
+			"definitions": {
+				"tls": {
+					"type": "object",
+					"default": {},
+					"properties": {
+						"enabled": { "default": false }
+					}
+				}
+			}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var v interface{}
+		err = json.Unmarshal([]byte(`{"hosts": ["example.com"]}`), &v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = schema.Validate(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, found := v.(map[string]interface{})["port"]; found {
+			t.Fatalf("%s: expected no defaults without WithDefaults", dialect)
+		}
+
+		v, err = schema.ValidateValue(v, WithDefaults())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, _ := json.Marshal(v)
+		expected := `{"hosts":["example.com","localhost"],"port":8080,"tls":{"enabled":false}}`
+		if string(data) != expected {
+			t.Errorf("%s: expected %s but was %s", dialect, expected, data)
 		}
 	}
 
-	load_test_json(path, &suite)
+	// recursive defaults are filled in once
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"properties": {
+			"name": { "default": "node" },
+			"child": { "$ref": "#", "default": {} }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	var (
-		passed int
-		failed int
-	)
+	v, err := schema.ValidateValue(map[string]interface{}{}, WithDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(v)
+	expected := `{"child":{"name":"node"},"name":"node"}`
+	if string(data) != expected {
+		t.Errorf("expected %s but was %s", expected, data)
+	}
+
+	// the defaults of the meta-schemas are recursive too
+	schema, err = RootEnv.BuildSchema("", []byte(`{"$ref": "http://json-schema.org/draft-07/schema#"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err = schema.ValidateValue(map[string]interface{}{}, WithDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ = json.Marshal(v)
+	expected = `{"additionalItems":true,"additionalProperties":true,"contains":true,"definitions":{},"else":true,"if":true,"items":true,"not":true,"patternProperties":{},"properties":{},"propertyNames":true,"readOnly":false,"required":[],"then":true,"uniqueItems":false,"writeOnly":false}`
+	if string(data) != expected {
+		t.Errorf("expected %s but was %s", expected, data)
+	}
+}
+
+func TestWithTypeCoercion(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"properties": {
+			"page": { "type": "integer", "minimum": 1 },
+			"ratio": { "type": "number" },
+			"debug": { "type": "boolean" },
+			"cursor": { "type": ["null", "integer"] },
+			"name": { "type": ["integer", "string"] },
+			"tags": { "type": "array", "items": { "type": "integer" } }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newInput := func() interface{} {
+		return map[string]interface{}{
+			"page":   "2",
+			"ratio":  "0.5",
+			"debug":  "true",
+			"cursor": "",
+			"name":   "42",
+			"tags":   "7",
+		}
+	}
+
+	if err := schema.Validate(newInput()); err == nil {
+		t.Fatal("expected an error without WithTypeCoercion")
+	}
+
+	if err := schema.Validate(newInput(), WithTypeCoercion()); err == nil {
+		t.Fatal("expected an error for the scalar tags without WithArrayCoercion")
+	}
+
+	v, err := schema.ValidateValue(newInput(), WithArrayCoercion())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(v)
+	expected := `{"cursor":null,"debug":true,"name":"42","page":2,"ratio":0.5,"tags":[7]}`
+	if string(data) != expected {
+		t.Errorf("expected %s but was %s", expected, data)
+	}
+
+	for _, input := range []string{"0", "1.5", "x", "0x10", "Inf", " 1"} {
+		err := schema.Validate(map[string]interface{}{"page": input}, WithTypeCoercion())
+		if err == nil {
+			t.Errorf("expected %q to be invalid", input)
+		}
+	}
+
+	// draft-03 `disallow` doesn't coerce the values it rejects
+	schema, err = RootEnv.BuildSchema("", []byte(`{
+		"$schema": "http://json-schema.org/draft-03/schema#",
+		"disallow": "integer"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err = schema.ValidateValue("42", WithTypeCoercion())
+	if err != nil || v != "42" {
+		t.Errorf("expected %q to be allowed as is but was %#v (%v)", "42", v, err)
+	}
+	if err := schema.Validate(int64(42), WithTypeCoercion()); err == nil {
+		t.Error("expected 42 to be disallowed")
+	}
+
+	// the coercions of a failing branch are not kept
+	schema, err = RootEnv.BuildSchema("", []byte(`{
+		"anyOf": [
+			{ "properties": { "n": { "type": "integer" } }, "required": ["x"] },
+			{ "properties": { "n": { "type": "string" } } }
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schema.Validate(map[string]interface{}{"n": "5"}, WithTypeCoercion()); err != nil {
+		t.Errorf("Validate: unexpected error: %s", err)
+	}
+	if err := schema.ValidateData([]byte(`{"n":"5"}`), WithTypeCoercion()); err != nil {
+		t.Errorf("ValidateData: unexpected error: %s", err)
+	}
+}
+
+func TestWithFormatAssertion(t *testing.T) {
+	for _, dialect := range []string{
+		"https://json-schema.org/draft/2019-09/schema",
+		"https://json-schema.org/draft/2020-12/schema",
+	} {
+		schema, err := RootEnv.BuildSchema("", []byte(`{
+			"$schema": "`+dialect+`",
+			"properties": {
+				"email": { "format": "email" },
+				"code": { "format": "x-unknown" }
+			}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := schema.ValidateData([]byte(`{"email": "2962", "code": "x"}`)); err != nil {
+			t.Errorf("%s: format is only an annotation by default: %s", dialect, err)
+		}
+		if err := schema.ValidateData([]byte(`{"email": "2962"}`), WithFormatAssertion()); err == nil {
+			t.Errorf("%s: expected an error with WithFormatAssertion", dialect)
+		}
+		if err := schema.ValidateData([]byte(`{"email": "joe@example.com", "code": "x"}`), WithFormatAssertion()); err != nil {
+			t.Errorf("%s: unexpected error: %s", dialect, err)
+		}
+	}
 
+	// the format-assertion vocabulary makes format an assertion
 	env := RootEnv.Clone()
-	env.Transport = &testTransport{}
+	_, err := env.RegisterSchema("", []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "http://localhost:1234/format-assertion.json",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/core": true,
+			"https://json-schema.org/draft/2020-12/vocab/format-assertion": true
+		},
+		"$dynamicAnchor": "meta",
+		"allOf": [
+			{"$ref": "https://json-schema.org/draft/2020-12/meta/core"},
+			{"$ref": "https://json-schema.org/draft/2020-12/meta/format-assertion"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := env.BuildSchema("", []byte(`{
+		"$schema": "http://localhost:1234/format-assertion.json",
+		"format": "email"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Validate("2962"); err == nil {
+		t.Error("expected an error with the format-assertion vocabulary")
+	}
+	if err := schema.Validate("joe@example.com"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestWithRemoveAdditional(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"properties": {
+			"id": { "type": "integer" },
+			"meta": {
+				"properties": { "source": { "type": "string" } },
+				"additionalProperties": false
+			},
+			"labels": {
+				"properties": {},
+				"additionalProperties": { "type": "string" }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const input = `{
+		"id": 1,
+		"extra": true,
+		"meta": { "source": "import", "trace/id": "x" },
+		"labels": { "env": "prod", "replicas": 3 }
+	}`
+
+	for _, test := range []struct {
+		mode     RemoveAdditionalMode
+		valid    bool
+		expected string
+		removed  []string
+	}{
+		{
+			mode:     RemoveAllAdditional,
+			valid:    true,
+			expected: `{"id":1,"labels":{},"meta":{"source":"import"}}`,
+			removed:  []string{"/extra", "/labels/env", "/labels/replicas", "/meta/trace~1id"},
+		},
+		{
+			mode:     RemoveDeniedAdditional,
+			valid:    false,
+			expected: `{"extra":true,"id":1,"labels":{"env":"prod","replicas":3},"meta":{"source":"import"}}`,
+			removed:  []string{"/meta/trace~1id"},
+		},
+		{
+			mode:     RemoveFailingAdditional,
+			valid:    true,
+			expected: `{"extra":true,"id":1,"labels":{"env":"prod"},"meta":{"source":"import"}}`,
+			removed:  []string{"/labels/replicas", "/meta/trace~1id"},
+		},
+	} {
+		var v interface{}
+		dec := json.NewDecoder(strings.NewReader(input))
+		dec.UseNumber()
+		err := dec.Decode(&v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var removed []string
+		v, err = schema.ValidateValue(v, WithRemoveAdditional(test.mode, func(location string) {
+			removed = append(removed, location)
+		}))
+		if test.valid && err != nil {
+			t.Errorf("mode %d: unexpected error: %s", test.mode, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("mode %d: expected an error", test.mode)
+		}
+
+		data, _ := json.Marshal(v)
+		if string(data) != test.expected {
+			t.Errorf("mode %d: expected %s but was %s", test.mode, test.expected, data)
+		}
+
+		sort.Strings(removed)
+		if !reflect.DeepEqual(removed, test.removed) {
+			t.Errorf("mode %d: expected %v to be removed but was %v", test.mode, test.removed, removed)
+		}
+	}
+
+	// the removals of a failing branch are neither kept nor reported
+	schema, err = RootEnv.BuildSchema("", []byte(`{
+		"anyOf": [
+			{ "properties": { "a": {} }, "additionalProperties": false, "required": ["x"] },
+			{ "properties": { "b": {} } }
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var removed []string
+	v, err := schema.ValidateValue(map[string]interface{}{"b": json.Number("1")},
+		WithRemoveAdditional(RemoveDeniedAdditional, func(location string) {
+			removed = append(removed, location)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(v)
+	if string(data) != `{"b":1}` || len(removed) != 0 {
+		t.Errorf(`expected {"b":1} without removals but was %s (removed %v)`, data, removed)
+	}
+
+	// the parts of an allOf don't see each other's properties
+	schema, err = RootEnv.BuildSchema("", []byte(`{
+		"allOf": [{ "properties": { "a": {} } }, { "properties": { "b": {} } }]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err = schema.ValidateValue(map[string]interface{}{"a": int64(1), "b": int64(2), "c": int64(3)},
+		WithRemoveAdditional(RemoveAllAdditional, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ = json.Marshal(v)
+	if string(data) != `{}` {
+		t.Errorf(`expected {} but was %s`, data)
+	}
+}
+
+func TestValidateDoesNotModifyInput(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"properties": {
+			"count": { "type": "integer" },
+			"enabled": { "type": "boolean", "default": true },
+			"items": {
+				"type": "array",
+				"items": {
+					"properties": { "size": { "type": "number" } },
+					"additionalProperties": false
+				}
+			},
+			"shared": { "type": "object" }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var input interface{}
+	dec := json.NewDecoder(strings.NewReader(`{
+		"count": 3,
+		"items": [{ "size": "1.5", "tmp": 1 }, { "size": 2 }],
+		"shared": { "a": [1, 2] }
+	}`))
+	dec.UseNumber()
+	if err := dec.Decode(&input); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := deepCopy(input)
+
+	if err := schema.Validate(input); err == nil {
+		t.Fatal("expected an error for the string size")
+	}
+	if !reflect.DeepEqual(input, snapshot) {
+		t.Fatalf("Validate modified the input")
+	}
+
+	v, err := schema.ValidateValue(input,
+		WithDefaults(),
+		WithTypeCoercion(),
+		WithRemoveAdditional(RemoveDeniedAdditional, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(input, snapshot) {
+		t.Fatalf("ValidateValue modified the input")
+	}
+
+	data, _ := json.Marshal(v)
+	expected := `{"count":3,"enabled":true,"items":[{"size":1.5},{"size":2}],"shared":{"a":[1,2]}}`
+	if string(data) != expected {
+		t.Errorf("expected %s but was %s", expected, data)
+	}
+
+	y := v.(map[string]interface{})
+	if _, ok := y["count"].(int64); !ok {
+		t.Errorf("expected count to be normalized to int64 but was %T", y["count"])
+	}
+	if !sameValue(y["shared"], input.(map[string]interface{})["shared"]) {
+		t.Errorf("expected the unchanged members to be shared with the input")
+	}
+}
+
+func TestDecodeValid(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"properties": {
+			"name": { "type": "string" },
+			"port": { "type": "integer", "default": 8080 },
+			"created": { "type": "string", "format": "date-time" },
+			"lines": {
+				"type": "array",
+				"items": {
+					"required": ["sku"],
+					"properties": {
+						"sku": { "type": "string" },
+						"quantity": { "type": "integer" }
+					}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type line struct {
+		SKU      string `json:"sku"`
+		Quantity int8   `json:"quantity"`
+	}
+	type Meta struct {
+		Created time.Time `json:"created"`
+	}
+	type order struct {
+		*Meta
+		Name  string
+		Port  uint16  `json:"port"`
+		Lines []*line `json:"lines"`
+		Extra interface{}
+	}
+
+	var out order
+	err = schema.DecodeValid([]byte(`{
+		"name": "shop",
+		"port": "443",
+		"created": "2020-01-02T03:04:05Z",
+		"lines": [{ "sku": "a", "quantity": 2 }],
+		"extra": { "x": [true] }
+	}`), &out, WithTypeCoercion(), WithDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Name != "shop" || out.Port != 443 || len(out.Lines) != 1 || *out.Lines[0] != (line{"a", 2}) {
+		t.Errorf("unexpected result: %+v", out)
+	}
+	if out.Meta == nil || !out.Created.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("expected the embedded created time to be decoded")
+	}
+	if !reflect.DeepEqual(out.Extra, map[string]interface{}{"x": []interface{}{true}}) {
+		t.Errorf("unexpected extra: %#v", out.Extra)
+	}
+
+	out = order{}
+	err = schema.DecodeValid([]byte(`{}`), &out, WithDefaults())
+	if err != nil || out.Port != 8080 {
+		t.Errorf("expected the default port but was %d (%v)", out.Port, err)
+	}
+
+	err = schema.DecodeValid([]byte(`{"lines": [{ "sku": "a" }, {}]}`), &out)
+	var e *ErrRequiredProperty
+	if !errors.As(err, &e) || e.InstanceLocation() != "/lines/1" {
+		t.Errorf("expected a required property error at /lines/1 but was %v", err)
+	}
+
+	err = schema.DecodeValid([]byte(`{"lines": [{ "sku": "a" }, { "sku": "b", "quantity": 1000 }]}`), &out)
+	var d *DecodeError
+	if !errors.As(err, &d) || d.InstanceLocation() != "/lines/1/quantity" {
+		t.Errorf("expected a decode error at /lines/1/quantity but was %v", err)
+	}
+
+	if err := schema.DecodeValid([]byte(`{}`), out); err == nil {
+		t.Errorf("expected an error for a non-pointer target")
+	}
+
+	// the `string` option is handled like encoding/json does
+	type quoted struct {
+		ID    int64   `json:"id,string"`
+		Ratio *string `json:"ratio,string"`
+		Tags  []int   `json:"tags,string"`
+	}
+
+	empty, err := RootEnv.BuildSchema("", []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var q quoted
+	err = empty.DecodeValid([]byte(`{"id":"5","ratio":"\"0.5\"","tags":[1]}`), &q)
+	if err != nil || q.ID != 5 || q.Ratio == nil || *q.Ratio != "0.5" || !reflect.DeepEqual(q.Tags, []int{1}) {
+		t.Errorf("unexpected result: %+v (%v)", q, err)
+	}
+
+	for _, input := range []string{`{"id":5}`, `{"id":"x"}`, `{"id":"\"5\""}`, `{"ratio":"0.5"}`} {
+		err = empty.DecodeValid([]byte(input), &q)
+		if !errors.As(err, &d) {
+			t.Errorf("expected a decode error for %s but was %v", input, err)
+		}
+	}
+
+	// the removals of a failing branch are not kept
+	schema, err = RootEnv.BuildSchema("", []byte(`{
+		"anyOf": [
+			{ "properties": { "a": {} }, "additionalProperties": false, "required": ["x"] },
+			{ "properties": { "b": {} } }
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m map[string]interface{}
+	err = schema.DecodeValid([]byte(`{"b":1}`), &m, WithRemoveAdditional(RemoveDeniedAdditional, nil))
+	if err != nil || !reflect.DeepEqual(m, map[string]interface{}{"b": json.Number("1")}) {
+		t.Errorf(`expected {"b":1} but was %v (%v)`, m, err)
+	}
+}
+
+type reflectAddress struct {
+	Street string  `json:"street" jsonschema:"minLength=1"`
+	Zip    *string `json:"zip,omitempty" jsonschema:"pattern=^[0-9]{4\\,5}$"`
+}
+
+type reflectPerson struct {
+	Name     string          `json:"name" jsonschema:"minLength=1,maxLength=10"`
+	Age      uint8           `json:"age,omitempty" jsonschema:"maximum=150"`
+	Role     string          `json:"role" jsonschema:"enum=admin,enum=user"`
+	Born     time.Time       `json:"born"`
+	Home     *reflectAddress `json:"home"`
+	Tags     []string        `json:"tags,omitempty"`
+	Parent   *reflectPerson  `json:"parent,omitempty"`
+	Labels   map[string]int  `json:"labels,omitempty"`
+	Extra    interface{}     `json:"-"`
+	internal int
+}
+
+func TestValidateGoValues(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"required": ["name", "created"],
+		"properties": {
+			"name": { "type": "string", "minLength": 1 },
+			"port": { "type": "integer", "maximum": 65535 },
+			"ratio": { "type": "number", "multipleOf": 0.1 },
+			"created": { "type": "string", "format": "date-time" },
+			"tags": { "type": "array", "items": { "type": "string" }, "uniqueItems": true },
+			"labels": { "type": "object", "additionalProperties": { "type": "string" } },
+			"note": { "type": "string" },
+			"extra": {
+				"type": "object",
+				"properties": {
+					"1": { "type": "array", "items": { "type": "integer" } }
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Meta struct {
+		Created time.Time         `json:"created"`
+		Labels  map[string]string `json:"labels,omitempty"`
+	}
+	type service struct {
+		*Meta
+		Name   string      `json:"name"`
+		Port   uint32      `json:"port"`
+		Ratio  float32     `json:"ratio"`
+		Tags   []string    `json:"tags,omitempty"`
+		Note   *string     `json:"note,omitempty"`
+		Extra  interface{} `json:"extra,omitempty"`
+		hidden int
+	}
+
+	valid := service{
+		Meta:  &Meta{Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), Labels: map[string]string{"env": "prod"}},
+		Name:  "web",
+		Port:  443,
+		Ratio: 0.3,
+		Tags:  []string{"a", "b"},
+		Extra: map[interface{}]interface{}{1: []interface{}{1, uint8(2)}},
+	}
+	if err := schema.Validate(valid); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := schema.Validate(&valid); err != nil {
+		t.Errorf("unexpected error for a pointer: %s", err)
+	}
+
+	tests := []struct {
+		value     service
+		locations []string
+	}{
+		// the created time of the nil Meta is missing
+		{service{Name: "web"}, []string{""}},
+		{service{Meta: valid.Meta, Name: "web", Port: 70000}, []string{"/port"}},
+		{service{Meta: valid.Meta, Name: "web", Ratio: 0.25}, []string{"/ratio"}},
+		{service{Meta: valid.Meta, Name: "web", Tags: []string{"a", "a"}}, []string{"/tags"}},
+		{service{Meta: valid.Meta, Name: "", Extra: map[interface{}]interface{}{1: []interface{}{"x"}}}, []string{"/extra/1/0", "/name"}},
+	}
+
+	var leaves func(err error) []string
+	leaves = func(err error) []string {
+		if e, ok := err.(interface{ Unwrap() []error }); ok && len(e.Unwrap()) > 0 {
+			var locations []string
+			for _, err := range e.Unwrap() {
+				locations = append(locations, leaves(err)...)
+			}
+			return locations
+		}
+		if e, ok := err.(interface{ Unwrap() error }); ok && e.Unwrap() != nil {
+			return leaves(e.Unwrap())
+		}
+		if e, ok := err.(Error); ok {
+			return []string{e.InstanceLocation()}
+		}
+		return nil
+	}
+
+	for i, test := range tests {
+		err := schema.Validate(test.value)
+		locations := leaves(err)
+		sort.Strings(locations)
+
+		if !reflect.DeepEqual(locations, test.locations) {
+			t.Errorf("%d: expected errors at %v but were at %v (%v)", i, test.locations, locations, err)
+		}
+	}
+
+	err = schema.Validate(map[string]string{"name": "web", "created": "2020-01-02T03:04:05Z"})
+	if err != nil {
+		t.Errorf("unexpected error for a map[string]string: %s", err)
+	}
+
+	list, err := RootEnv.BuildSchema("", []byte(`{"items": {"type": "string"}, "maxItems": 2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Validate([]string{"a", "b"}); err != nil {
+		t.Errorf("unexpected error for a []string: %s", err)
+	}
+	if err := list.Validate([3]string{"a", "b", "c"}); err == nil {
+		t.Errorf("expected an error for 3 items")
+	}
+}
+
+type validatedLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity" jsonschema:"minimum=1"`
+}
+
+type validatedAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+func (a validatedAddress) SchemaId() string {
+	return "http://example.com/validated.json#/definitions/address"
+}
+
+// validatedEnv is the Env of the schema of validatedPet (see TestValidated).
+var validatedEnv *Env
+
+type validatedPet struct {
+	Name string `json:"name"`
+}
+
+func (p *validatedPet) SchemaId() string { return "http://example.com/pets.json#/definitions/pet" }
+func (p *validatedPet) SchemaEnv() *Env  { return validatedEnv }
+
+func TestValidated(t *testing.T) {
+	_, err := RootEnv.RegisterSchema("", []byte(`{
+		"id": "http://example.com/validated.json#",
+		"definitions": {
+			"address": {
+				"type": "object",
+				"required": ["street", "city"],
+				"properties": { "city": { "minLength": 2 } }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type request struct {
+		Address Validated[validatedAddress]  `json:"address"`
+		Lines   []Validated[validatedLine]   `json:"lines"`
+		Extra   *Validated[validatedAddress] `json:"extra"`
+	}
+
+	var req request
+	err = json.Unmarshal([]byte(`{
+		"address": { "street": "Main", "city": "Gent" },
+		"lines": [{ "sku": "a", "quantity": 2 }]
+	}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Address.Value.City != "Gent" || len(req.Lines) != 1 || req.Lines[0].Value != (validatedLine{"a", 2}) || req.Extra != nil {
+		t.Errorf("unexpected result: %+v", req)
+	}
+
+	data, err := json.Marshal(req.Lines)
+	if err != nil || string(data) != `[{"sku":"a","quantity":2}]` {
+		t.Errorf("unexpected encoding %s (%v)", data, err)
+	}
+
+	tests := []struct {
+		data     string
+		code     ErrorCode
+		location string
+	}{
+		{`{"address": {"street": "Main"}}`, CodeRequiredProperty, ""},
+		{`{"address": {"street": "Main", "city": "G"}}`, CodeTooShort, "/city"},
+		{`{"lines": [{"sku": "a", "quantity": 1}, {"sku": "b", "quantity": 0}]}`, CodeTooSmall, "/quantity"},
+		{`{"lines": [{"sku": 1, "quantity": 1}]}`, CodeInvalidType, "/sku"},
+	}
+
+	for _, test := range tests {
+		var req request
+		err := json.Unmarshal([]byte(test.data), &req)
+
+		var invalid *ErrInvalidInstance
+		if !errors.As(err, &invalid) {
+			t.Errorf("%s: expected a validation error but was %v", test.data, err)
+			continue
+		}
+
+		var leaf error = invalid
+		for {
+			if e, ok := leaf.(*ErrInvalidInstance); ok && len(e.Errors) == 1 {
+				leaf = e.Errors[0]
+			} else if e, ok := leaf.(interface{ Unwrap() error }); ok && e.Unwrap() != nil {
+				leaf = e.Unwrap()
+			} else {
+				break
+			}
+		}
+		if e, ok := leaf.(Error); !ok || e.Code() != test.code || e.InstanceLocation() != test.location {
+			t.Errorf("%s: unexpected error %v", test.data, leaf)
+		}
+	}
+
+	// the schema is registered in the Env of the type
+	validatedEnv = RootEnv.Clone()
+	_, err = validatedEnv.RegisterSchema("", []byte(`{
+		"id": "http://example.com/pets.json#",
+		"definitions": {
+			"pet": { "required": ["name"], "properties": { "name": { "minLength": 1 } } }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := RootEnv.Schema("http://example.com/pets.json"); found {
+		t.Fatal("expected the schema to be registered in validatedEnv only")
+	}
+
+	var pet Validated[validatedPet]
+	if err := json.Unmarshal([]byte(`{"name": "rex"}`), &pet); err != nil || pet.Value.Name != "rex" {
+		t.Errorf("unexpected result: %+v (%v)", pet, err)
+	}
+	if err := json.Unmarshal([]byte(`{"name": ""}`), &pet); err == nil {
+		t.Errorf("expected an error for the empty name")
+	}
+}
+
+func TestRequestValidator(t *testing.T) {
+	doc, err := RootEnv.Clone().LoadOpenAPI("", []byte(`{
+		"openapi": "3.0.3",
+		"paths": {
+			"/orders/{id}/lines": {
+				"post": {
+					"parameters": [
+						{ "name": "id", "in": "path", "required": true, "schema": { "type": "integer" } },
+						{ "name": "limit", "in": "query", "schema": { "type": "integer", "maximum": 10 } },
+						{ "name": "tag", "in": "query", "schema": { "type": "array", "items": { "type": "string" } } },
+						{ "name": "X-Trace", "in": "header", "required": true, "schema": { "type": "string" } }
+					],
+					"requestBody": {
+						"required": true,
+						"content": {
+							"application/json": {
+								"schema": {
+									"type": "object",
+									"required": ["sku"],
+									"properties": {
+										"sku": { "type": "string" },
+										"quantity": { "type": "integer", "minimum": 1, "default": 1 }
+									}
+								}
+							}
+						}
+					}
+				}
+			},
+			"/orders/search": {
+				"post": {
+					"requestBody": {
+						"content": { "application/json": { "schema": { "type": "object" } } }
+					}
+				}
+			},
+			"/files/{name}": {
+				"get": {
+					"parameters": [
+						{ "name": "name", "in": "path", "required": true, "schema": { "pattern": "^[a-z/]+$" } }
+					]
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var validated *ValidatedRequest
+	handler := (&RequestValidator{
+		Routes:  OpenAPIRoutes(doc),
+		Options: []ValidateOption{WithDefaults()},
+	}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		validated, _ = ValidatedRequestFrom(r.Context())
+
+		data, _ := ioutil.ReadAll(r.Body)
+		w.Write(data)
+	}))
+
+	tests := []struct {
+		method, target, contentType, body string
+		status                            int
+		pointers                          []string
+	}{
+		{"POST", "/orders/7/lines?limit=5&tag=a&tag=b", "application/json", `{"sku": "a"}`, http.StatusOK, nil},
+		{"POST", "/orders/7/lines?tag=a", "application/json; charset=utf-8", `{"sku": "a", "quantity": 2}`, http.StatusOK, nil},
+		{"GET", "/orders/x/lines", "", "", http.StatusOK, nil},
+		{"POST", "/orders/x/lines?limit=20", "application/json", `{"quantity": 0}`, http.StatusBadRequest, []string{"body:", "body:/quantity", "path:/id", "query:/limit"}},
+		{"POST", "/orders/7/lines", "application/json", `{"sku": `, http.StatusBadRequest, nil},
+		{"POST", "/orders/7/lines", "text/plain", `{"sku": "a"}`, http.StatusUnsupportedMediaType, nil},
+		{"POST", "/orders/7/lines", "", "", http.StatusUnsupportedMediaType, nil},
+		{"POST", "/orders/search", "", "", http.StatusOK, nil},
+		{"POST", "/orders/search", "application/json", `[]`, http.StatusBadRequest, []string{"body:"}},
+		{"GET", "/files/a%2Fb", "", "", http.StatusOK, nil},
+		{"GET", "/files/a%2F1", "", "", http.StatusBadRequest, []string{"path:/name"}},
+	}
+
+	for _, test := range tests {
+		validated = nil
+
+		r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		if test.contentType != "" {
+			r.Header.Set("Content-Type", test.contentType)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != test.status {
+			t.Errorf("%s %s: expected status %d but was %d (%s)", test.method, test.target, test.status, w.Code, w.Body)
+			continue
+		}
+		if w.Code == http.StatusOK {
+			if w.Body.String() != test.body {
+				t.Errorf("%s %s: expected the body to be passed on but was %s", test.method, test.target, w.Body)
+			}
+			continue
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Errorf("%s %s: unexpected content type %q", test.method, test.target, ct)
+		}
+
+		var problem Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatal(err)
+		}
+		if problem.Status != test.status || problem.Title == "" {
+			t.Errorf("%s %s: unexpected problem %s", test.method, test.target, w.Body)
+		}
+
+		var pointers []string
+		for _, e := range problem.Errors {
+			pointers = append(pointers, e.In+":"+e.Pointer)
+		}
+		sort.Strings(pointers)
+		if !reflect.DeepEqual(pointers, test.pointers) {
+			t.Errorf("%s %s: expected errors at %v but were at %v", test.method, test.target, test.pointers, pointers)
+		}
+	}
+
+	r := httptest.NewRequest("POST", "/orders/7/lines?limit=5&tag=a&tag=b", strings.NewReader(`{"sku": "a"}`))
+	r.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if validated == nil {
+		t.Fatal("expected the validated request in the context")
+	}
+	if validated.Route == nil || validated.Route.Path != "/orders/{id}/lines" {
+		t.Errorf("unexpected route %#v", validated.Route)
+	}
+	data, _ := json.Marshal([]interface{}{validated.Body, validated.Query, validated.Path})
+	if s := `[{"quantity":1,"sku":"a"},{"limit":5,"tag":["a","b"]},{"id":7}]`; string(data) != s {
+		t.Errorf("expected %s but was %s", s, data)
+	}
+	if _, ok := validated.Path["id"].(int64); !ok {
+		t.Errorf("expected the path parameter to be converted to an int64 but was %T", validated.Path["id"])
+	}
+
+	// the parameters are unescaped after the path is split
+	validated = nil
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/files/a%2Fb", nil))
+	if validated == nil || validated.Path["name"] != "a/b" {
+		t.Errorf("expected the path parameter a/b but was %#v", validated)
+	}
+
+	// bodies of unknown length (like chunked bodies) are peeked at
+	for body, status := range map[string]int{"": http.StatusOK, `{"a": 1}`: http.StatusOK, `[]`: http.StatusBadRequest} {
+		r := httptest.NewRequest("POST", "/orders/search", io.MultiReader(strings.NewReader(body)))
+		if body != "" {
+			r.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != status || (status == http.StatusOK && w.Body.String() != body) {
+			t.Errorf("chunked %q: expected status %d but was %d (%s)", body, status, w.Code, w.Body)
+		}
+	}
+
+	// literal segments are preferred over parameters
+	routes := []*Route{
+		{Path: "/orders/{id}/{line}"},
+		{Path: "/orders/{id}/lines"},
+		{Method: "GET", Path: "/orders/{id}/lines"},
+		{Path: "/orders/search/{line}"},
+	}
+	for target, expected := range map[string]*Route{
+		"/orders/search/lines": routes[3],
+		"/orders/7/lines":      routes[2],
+		"/orders/7/x":          routes[0],
+	} {
+		route, _ := matchRoute(routes, httptest.NewRequest("GET", target, nil))
+		if route != expected {
+			t.Errorf("%s: expected route %#v but was %#v", target, expected, route)
+		}
+	}
+}
+
+func TestContractTransport(t *testing.T) {
+	env := RootEnv.Clone()
+
+	doc, err := env.LoadOpenAPI("", []byte(`{
+		"openapi": "3.0.3",
+		"paths": {
+			"/pets/{id}": {
+				"get": {
+					"responses": {
+						"200": {
+							"content": {
+								"application/json": {
+									"schema": {
+										"type": "object",
+										"required": ["id", "name"],
+										"properties": {
+											"id": { "type": "integer" },
+											"name": { "type": "string" },
+											"tags": { "type": "array", "items": { "type": "string" } }
+										}
+									}
+								}
+							}
+						},
+						"4XX": {
+							"content": {
+								"application/problem+json": {
+									"schema": { "required": ["title"] }
+								}
+							}
+						},
+						"304": { "description": "not modified" }
+					}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.RegisterSchema("http://example.com/health.json", []byte(`{
+		"type": "object",
+		"properties": { "status": { "enum": ["ok", "degraded"] } }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	health, found := env.Schema("http://example.com/health.json")
+	if !found {
+		t.Fatal("expected the health schema to be registered")
+	}
+
+	routes := append(OpenAPIRoutes(doc), &Route{
+		Method:    "GET",
+		Path:      "/health",
+		Responses: map[string]map[string]*Schema{"200": {"application/json": health}},
+	})
+
+	responses := map[string]struct {
+		status      int
+		contentType string
+		body        string
+	}{
+		"/pets/1":   {200, "application/json", `{"id": 1, "name": "rex"}`},
+		"/pets/2":   {200, "application/json; charset=utf-8", `{"id": "2", "tags": ["a", 3]}`},
+		"/pets/3":   {404, "application/problem+json", `{"status": 404}`},
+		"/pets/4":   {200, "text/html", `<p>rex</p>`},
+		"/pets/5":   {500, "text/plain", `oops`},
+		"/pets/6":   {304, "", ""},
+		"/health":   {200, "application/json", `{"status": "down"}`},
+		"/unrouted": {200, "application/json", `[]`},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := responses[r.URL.Path]
+		w.Header().Set("Content-Type", resp.contentType)
+		w.WriteHeader(resp.status)
+		io.WriteString(w, resp.body)
+	}))
+	defer server.Close()
+
+	contract := CheckTestServer(server, routes)
+
+	paths := make([]string, 0, len(responses))
+	for path := range responses {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		resp, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(data) != responses[path].body {
+			t.Errorf("%s: expected the body to be returned unchanged but was %s", path, data)
+		}
+	}
+
+	var violations []string
+	for _, v := range contract.Violations() {
+		violations = append(violations, fmt.Sprintf("%s %d %s %s", v.Request.URL.Path, v.Status, v.Pointer, v.Keyword))
+	}
+	sort.Strings(violations)
+
+	expected := []string{
+		"/health 200 /status enum",
+		"/pets/2 200  required",
+		"/pets/2 200 /id type",
+		"/pets/2 200 /tags/1 type",
+		"/pets/3 404  required",
+		"/pets/4 200  ",
+		"/pets/5 500  ",
+	}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("expected the violations\n%s\nbut were\n%s", strings.Join(expected, "\n"), strings.Join(violations, "\n"))
+	}
+}
+
+func TestValidateReader(t *testing.T) {
+	var paths []string
+	for _, dir := range []string{"draft3", "draft4", "draft6", "draft7", "draft2019-09", "draft2020-12", "openapi3"} {
+		matches, err := filepath.Glob("testdata/" + dir + "/*.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, matches...)
+	}
+
+	var compared int
+	for _, path := range paths {
+		var suite []struct {
+			Description string          `json:"description"`
+			SchemaDef   json.RawMessage `json:"schema"`
+			Tests       []struct {
+				Description string          `json:"description"`
+				Data        json.RawMessage `json:"data"`
+			}
+		}
+		load_test_json(strings.TrimPrefix(path, "testdata/"), &suite)
+
+		dir := filepath.Base(filepath.Dir(path))
+		env := RootEnv.Clone()
+		env.Transport = &testTransport{}
+		err := env.SetDefaultDialect(testSuiteDialects[dir])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, group := range suite {
+			schema, err := env.BuildSchema("", group.SchemaDef)
+			if err != nil {
+				t.Fatalf("%s: %s: %s", path, group.Description, err)
+			}
+
+			for _, test := range group.Tests {
+				expected := describeStreamError(schema.ValidateData(test.Data), "")
+				actual := describeStreamError(schema.ValidateReader(bytes.NewReader(test.Data)), "")
+				if actual != expected {
+					t.Errorf("%s: %s: %s: expected\n%s\nbut was\n%s", path, group.Description, test.Description, expected, actual)
+				}
+				compared++
+			}
+		}
+	}
+	t.Logf("compared %d instances", compared)
+
+	for def, streamable := range map[string]bool{
+		`{"type": "array", "items": {"$ref": "#/definitions/line"}, "definitions": {"line": {"required": ["sku"], "properties": {"sku": {"pattern": "^[a-z]+$"}}}}}`: true,
+		`{"allOf": [{"maxItems": 2}, {"items": {"enum": [1, 2]}}]}`: true,
+		`{"type": "array", "uniqueItems": true}`:                    false,
+		`{"oneOf": [{"type": "array"}, {"type": "object"}]}`:        false,
+	} {
+		schema, err := RootEnv.BuildSchema("", []byte(def))
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := &streamValidator{streamable: map[*Schema]bool{}}
+		if v.isStreamable(schema, map[*Schema]bool{}) != streamable {
+			t.Errorf("expected %s to be streamable: %v", def, streamable)
+		}
+	}
+
+	schema, err := RootEnv.BuildSchema("", []byte(`{"items": {"type": "integer"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.ValidateReader(strings.NewReader(`[1, 2, "a"`)); err == nil || errors.As(err, new(Error)) {
+		t.Errorf("expected a syntax error but was %v", err)
+	}
+}
+
+// describeStreamError describes the tree of err. The properties are
+// validated in random order so the nested errors are sorted and the values
+// of objects and arrays (which aren't kept by ValidateReader) are left out.
+func describeStreamError(err error, indent string) string {
+	if err == nil {
+		return indent + "<nil>"
+	}
+
+	e, ok := err.(Error)
+	if !ok {
+		return fmt.Sprintf("%s%T %s", indent, err, err)
+	}
+
+	var nested []error
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		nested = x.Unwrap()
+	case interface{ Unwrap() error }:
+		nested = []error{x.Unwrap()}
+	}
+
+	var children []string
+	for _, err := range nested {
+		children = append(children, describeStreamError(err, indent+"  "))
+	}
+	sort.Strings(children)
+
+	value := "-"
+	switch e.Value().(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		value = fmt.Sprintf("%#v", e.Value())
+	}
+
+	s := fmt.Sprintf("%s%T code=%s keyword=%q instance=%q location=%q value=%s",
+		indent, err, e.Code(), e.Keyword(), e.InstanceLocation(), e.KeywordLocation(), value)
+	for _, child := range children {
+		s += "\n" + child
+	}
+	return s
+}
+
+func TestValidateLines(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"type": "object",
+		"required": ["level", "msg"],
+		"properties": {
+			"level": { "enum": ["debug", "info", "error"] },
+			"msg": { "type": "string", "maxLength": 20 },
+			"tags": { "type": "array", "items": { "type": "string" }, "uniqueItems": true },
+			"n": { "type": "integer", "default": 0 }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		input bytes.Buffer
+		lines = map[int]string{}
+	)
+	for i := 1; i <= 3000; i++ {
+		var line string
+		switch i % 7 {
+		case 0:
+			line = fmt.Sprintf(`{"level": "warn", "msg": "line %d"}`, i)
+		case 3:
+			line = fmt.Sprintf(`{"level": "info", "msg": "line %d", "tags": ["a", "a"]}`, i)
+		case 5:
+			line = `{"level": "info", "msg": `
+		case 6:
+			line = "  \r"
+		default:
+			line = fmt.Sprintf(`{"level": "debug", "msg": "line %d", "n": %d}`, i, i)
+		}
+		if i%7 != 6 {
+			lines[i] = line
+		}
+		input.WriteString(line + "\r\n")
+	}
+
+	for _, workers := range []int{0, 1, 8} {
+		var (
+			next    = 1
+			results int
+		)
+
+		err := schema.ValidateLines(bytes.NewReader(input.Bytes()), workers, func(r *LineResult) error {
+			for next%7 == 6 {
+				next++
+			}
+			if r.Line != next {
+				t.Fatalf("%d workers: expected line %d but was %d", workers, next, r.Line)
+			}
+			next++
+			results++
+
+			if string(r.Data) != lines[r.Line] {
+				t.Errorf("%d workers: line %d: unexpected data %q", workers, r.Line, r.Data)
+			}
+
+			expected := describeStreamError(schema.ValidateData(r.Data), "")
+			if actual := describeStreamError(r.Err, ""); actual != expected {
+				t.Errorf("%d workers: line %d: expected\n%s\nbut was\n%s", workers, r.Line, expected, actual)
+			}
+			if r.Err == nil {
+				if n := r.Value.(map[string]interface{})["n"]; n == nil {
+					t.Errorf("%d workers: line %d: expected the validated value", workers, r.Line)
+				}
+			}
+			return nil
+		}, WithDefaults())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if results != len(lines) {
+			t.Errorf("%d workers: expected %d results but were %d", workers, len(lines), results)
+		}
+	}
+
+	stop := errors.New("stop")
+	var results int
+	err = schema.ValidateLines(bytes.NewReader(input.Bytes()), 4, func(r *LineResult) error {
+		results++
+		if r.Line == 100 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || results != 86 {
+		t.Errorf("expected to stop after 86 results but was %v after %d", err, results)
+	}
+}
+
+func TestSchemaConcurrentUse(t *testing.T) {
+	type instance struct {
+		schema   *Schema
+		data     []byte
+		expected string
+	}
+
+	var instances []instance
+	for _, dir := range []string{"draft4", "draft7", "draft2020-12"} {
+		paths, err := filepath.Glob("testdata/" + dir + "/*.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env := RootEnv.Clone()
+		env.Transport = &testTransport{}
+		err = env.SetDefaultDialect(testSuiteDialects[dir])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, path := range paths {
+			var suite []struct {
+				SchemaDef json.RawMessage `json:"schema"`
+				Tests     []struct {
+					Data json.RawMessage `json:"data"`
+				}
+			}
+			load_test_json(strings.TrimPrefix(path, "testdata/"), &suite)
+
+			for _, group := range suite {
+				schema, err := env.BuildSchema("", group.SchemaDef)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, test := range group.Tests {
+					expected := describeStreamError(schema.ValidateData(test.Data, WithDefaults()), "")
+					instances = append(instances, instance{schema, test.Data, expected})
+				}
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := range instances {
+				x := instances[(i*7+g)%len(instances)]
+				err := x.schema.ValidateData(x.data, WithDefaults())
+				if actual := describeStreamError(err, ""); actual != x.expected {
+					t.Errorf("expected\n%s\nbut was\n%s", x.expected, actual)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestReflector(t *testing.T) {
+	data, err := (&Reflector{}).Reflect(reflectPerson{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc["required"], []interface{}{"born", "home", "name", "role"}) {
+		t.Errorf("unexpected required properties: %v", doc["required"])
+	}
+	if _, found := doc["definitions"].(map[string]interface{})["reflectAddress"]; !found {
+		t.Errorf("expected reflectAddress in the definitions: %s", data)
+	}
+
+	schema, err := RootEnv.BuildSchema("", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"name": "Ann",
+			"role": "admin",
+			"born": "1990-01-02T03:04:05Z",
+			"home": map[string]interface{}{"street": "Main", "zip": "1234"},
+			"parent": map[string]interface{}{
+				"name": "Bob",
+				"role": "user",
+				"born": "1960-01-02T03:04:05Z",
+				"home": nil,
+			},
+		}
+	}
+
+	v, _ := json.Marshal(valid())
+	if err := schema.ValidateData(v); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for location, update := range map[string]func(v map[string]interface{}){
+		"/name":        func(v map[string]interface{}) { v["name"] = "" },
+		"/role":        func(v map[string]interface{}) { v["role"] = "guest" },
+		"/age":         func(v map[string]interface{}) { v["age"] = 200 },
+		"/home/zip":    func(v map[string]interface{}) { v["home"].(map[string]interface{})["zip"] = "12" },
+		"/parent/name": func(v map[string]interface{}) { v["parent"].(map[string]interface{})["name"] = "Bartholomew" },
+		"/labels/a":    func(v map[string]interface{}) { v["labels"] = map[string]interface{}{"a": "x"} },
+	} {
+		instance := valid()
+		update(instance)
+		v, _ := json.Marshal(instance)
+
+		err := schema.ValidateData(v)
+		var e Error
+		if !errors.As(err, &e) {
+			t.Errorf("%s: expected an error but was %v", location, err)
+			continue
+		}
+		for {
+			var inner []error
+			switch u := e.(type) {
+			case interface{ Unwrap() error }:
+				inner = []error{u.Unwrap()}
+			case interface{ Unwrap() []error }:
+				inner = u.Unwrap()
+			}
+			if len(inner) == 0 {
+				break
+			}
+			e = inner[0].(Error)
+		}
+		if e.InstanceLocation() != location {
+			t.Errorf("expected an error at %s but was at %s (%s)", location, e.InstanceLocation(), e)
+		}
+	}
+
+	if _, err := (&Reflector{}).Reflect(struct{ C chan int }{}); err == nil {
+		t.Errorf("expected an error for a channel field")
+	}
+}
+
+func TestGoGenerator(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"pets": { "type": "array", "items": { "$ref": "#/definitions/Pet" } }
+		},
+		"definitions": {
+			"Pet": {
+				"description": "A pet in the store.",
+				"type": "object",
+				"required": ["id", "name", "status"],
+				"properties": {
+					"id": { "type": "integer" },
+					"name": { "type": "string" },
+					"status": { "$ref": "#/definitions/Status" },
+					"tags": { "type": "array", "items": { "type": "string" } },
+					"born": { "type": "string", "format": "date-time" },
+					"owner": { "$ref": "#/definitions/Owner" },
+					"parent": { "$ref": "#/definitions/Pet" },
+					"weight": { "type": ["number", "null"] },
+					"timeout": { "x-go-type": "time.Duration" },
+					"nick-name": { "type": "string", "x-go-name": "Nickname" },
+					"kind": { "oneOf": [{ "$ref": "#/definitions/Cat" }, { "$ref": "#/definitions/Dog" }] },
+					"size": { "enum": [1, 2, 3] }
+				}
+			},
+			"Status": { "type": "string", "enum": ["available", "sold-out"] },
+			"Owner": { "type": "object", "properties": { "user_id": { "type": "string" } } },
+			"Cat": { "type": "object", "properties": { "meows": { "type": "boolean" } } },
+			"Dog": { "type": "object", "properties": { "barks": { "type": "boolean" } } },
+			"Shop": {
+				"allOf": [
+					{ "$ref": "#/definitions/Owner" },
+					{ "required": ["url"], "properties": { "url": { "type": "string" } } }
+				]
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGoGenerator("petstore")
+	g.AddType("store", schema)
+	src, err := g.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// gofmt aligns the fields
+	spaces := regexp.MustCompile(`[ \t]+`)
+
+	for _, expected := range []string{
+		"type Store struct {\n\tPets []Pet `json:\"pets,omitempty\"`\n}",
+		"// A pet in the store.\ntype Pet struct {",
+		"ID int64 `json:\"id\"`",
+		"Status Status `json:\"status\"`",
+		"Owner *Owner `json:\"owner,omitempty\"`",
+		"Parent *Pet `json:\"parent,omitempty\"`",
+		"Born *time.Time `json:\"born,omitempty\"`",
+		"Weight *float64 `json:\"weight,omitempty\"`",
+		"Timeout *time.Duration `json:\"timeout,omitempty\"`",
+		"Nickname *string `json:\"nick-name,omitempty\"`",
+		"Kind *PetKind `json:\"kind,omitempty\"`",
+		"type Status string",
+		"StatusSoldOut Status = \"sold-out\"",
+		"type PetSize int64",
+		"PetSize1 PetSize = 1",
+		"UserID *string `json:\"user_id,omitempty\"`",
+		"type PetKind struct {\n\tCat *Cat\n\tDog *Dog\n}",
+		"func (u *PetKind) UnmarshalJSON(data []byte) error {",
+		"type Shop struct {\n\tOwner\n\tURL string `json:\"url\"`\n}",
+	} {
+		if !strings.Contains(spaces.ReplaceAllString(string(src), " "), spaces.ReplaceAllString(expected, " ")) {
+			t.Errorf("expected the generated code to contain:\n%s\n\n%s", expected, src)
+		}
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "petstore.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("petstore", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("invalid generated code: %s\n%s", err, src)
+	}
+}
+
+func TestGoGeneratorUnion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go tool is not available")
+	}
+
+	// the variants share the optional label so only their required
+	// properties tell them apart
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"oneOf": [{ "$ref": "#/definitions/Card" }, { "$ref": "#/definitions/Transfer" }],
+		"definitions": {
+			"Card": {
+				"type": "object",
+				"required": ["number"],
+				"properties": { "number": { "type": "string" }, "label": { "type": "string" } }
+			},
+			"Transfer": {
+				"type": "object",
+				"required": ["iban"],
+				"properties": { "iban": { "type": "string" }, "label": { "type": "string" } }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGoGenerator("main")
+	g.AddType("payment", schema)
+	src, err := g.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	err = ioutil.WriteFile(filepath.Join(dir, "payment.go"), src, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func main() {
+	for _, data := range []string{
+		`+"`"+`{"label": "rent", "iban": "NL02ABNA0123456789"}`+"`"+`,
+		`+"`"+`{"label": "rent", "number": "4111111111111111"}`+"`"+`,
+		`+"`"+`{"label": "rent"}`+"`"+`,
+	} {
+		var p Payment
+		err := json.Unmarshal([]byte(data), &p)
+		fmt.Println(p.Card != nil, p.Transfer != nil, err != nil)
+	}
+}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(gobin, "run", "payment.go", "main.go")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GO111MODULE=off", "GOFLAGS=")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s\n%s\n%s", err, out, src)
+	}
+
+	expected := "false true false\ntrue false false\nfalse false true\n"
+	if string(out) != expected {
+		t.Errorf("expected:\n%s\nbut was:\n%s\n%s", expected, out, src)
+	}
+}
+
+func TestGoCompiler(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go tool is not available")
+	}
+
+	paths, err := filepath.Glob("testdata/draft4/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths = append(paths, "testdata/draft4/optional/format.json", "testdata/draft4/optional/zeroTerminatedFloats.json")
+
+	var (
+		c     = NewGoCompiler("main")
+		funcs []string
+	)
+
+	for i, p := range paths {
+		paths[i] = strings.TrimPrefix(p, "testdata/")
+
+		var suite []struct {
+			SchemaDef json.RawMessage `json:"schema"`
+		}
+		load_test_json(paths[i], &suite)
+
+		env := RootEnv.Clone()
+		env.Transport = &testTransport{}
+
+		for _, group := range suite {
+			schema, err := env.BuildSchema("", group.SchemaDef)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			name := fmt.Sprintf("Validate%d", len(funcs))
+			c.AddFunc(name, schema)
+			funcs = append(funcs, name)
+		}
+	}
+
+	src, err := c.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the program is built in a GOPATH of its own where the package is a
+	// link to this package
+	gopath := t.TempDir()
+	pkg := filepath.Join(gopath, "src", "github.com", "fd", "jsonschema")
+	dir := filepath.Join(gopath, "src", "compiled")
+	for _, d := range []string{filepath.Dir(pkg), dir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(wd, pkg); err != nil {
+		t.Skipf("cannot link the package: %s", err)
+	}
+
+	harness := strings.Replace(compilerHarness, "FUNCS", strings.Join(funcs, ", "), 1)
+	harness = strings.Replace(harness, "PATHS", fmt.Sprintf("%#v", paths), 1)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "compiled.go"), src, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(harness), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(gobin, "run", "compiled.go", "main.go", wd)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOPATH="+gopath, "GO111MODULE=off", "GOFLAGS=")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s\n%s", err, out)
+	}
+	t.Logf("%s", out)
+}
+
+// TestCompiledBenchmark checks that compiled_benchmark_test.go (used by
+// BenchmarkCompiledValid) is generated from the benchmark schema by this
+// version of GoCompiler. An outdated file is written again.
+func TestCompiledBenchmark(t *testing.T) {
+	env := RootEnv.Clone()
+	schema, err := env.BuildSchema("", load_test_data("draft4/benchmark/schema4.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewGoCompiler("jsonschema_test")
+	c.AddFunc("validate-benchmark", schema)
+	src, err := c.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile("compiled_benchmark_test.go")
+	if err == nil && bytes.Equal(data, src) {
+		return
+	}
+
+	err = ioutil.WriteFile("compiled_benchmark_test.go", src, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Error("compiled_benchmark_test.go was outdated (it is written again)")
+}
+
+// compilerHarness compares the errors of the compiled functions with the
+// errors of the interpreter for the instances of the test suites.
+const compilerHarness = `package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fd/jsonschema"
+)
+
+var (
+	funcs = []func(interface{}) error{FUNCS}
+	paths = PATHS
+)
+
+type transport string
+
+func (t transport) Get(rawurl string) ([]byte, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filepath.Join(string(t), "testdata/draft4/remotes", path.Clean(u.Path)))
+}
+
+func main() {
+	var (
+		root      = os.Args[1]
+		n         int
+		instances int
+		failed    int
+	)
+
+	for _, p := range paths {
+		var suite []struct {
+			Description string
+			Schema      json.RawMessage
+			Tests       []struct {
+				Description string
+				Data        json.RawMessage
+			}
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(root, "testdata", p))
+		if err != nil {
+			panic(err)
+		}
+		err = json.Unmarshal(data, &suite)
+		if err != nil {
+			panic(err)
+		}
+
+		env := jsonschema.RootEnv.Clone()
+		env.Transport = transport(root)
+
+		for _, group := range suite {
+			schema, err := env.BuildSchema("", group.Schema)
+			if err != nil {
+				panic(err)
+			}
+			validate := funcs[n]
+			n++
+
+			for _, test := range group.Tests {
+				var x interface{}
+				dec := json.NewDecoder(bytes.NewReader(test.Data))
+				dec.UseNumber()
+				err := dec.Decode(&x)
+				if err != nil {
+					panic(err)
+				}
+
+				instances++
+				expected := describe(schema.Validate(x), "")
+				actual := describe(validate(x), "")
+				if expected != actual {
+					failed++
+					fmt.Printf("%s: %s: %s:\nexpected:\n%s\nactual:\n%s\n\n", p, group.Description, test.Description, expected, actual)
+				}
+			}
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d of %d instances failed\n", failed, instances)
+		os.Exit(1)
+	}
+	fmt.Printf("%d instances passed\n", instances)
+}
+
+func describe(err error, indent string) string {
+	if err == nil {
+		return indent + "<nil>"
+	}
+
+	e, ok := err.(jsonschema.Error)
+	if !ok {
+		return fmt.Sprintf("%s%T %s", indent, err, err)
+	}
+
+	var children []string
+	switch x := err.(type) {
+	case *jsonschema.ErrInvalidInstance:
+		// the properties are validated in random order
+		for _, err := range x.Errors {
+			children = append(children, describe(err, indent+"  "))
+		}
+		sort.Strings(children)
+	case *jsonschema.ErrNotAllOf:
+		children = describeAll(x.Errors, indent+"  ")
+	case *jsonschema.ErrNotAnyOf:
+		children = describeAll(x.Errors, indent+"  ")
+	case *jsonschema.ErrNotOneOf:
+		children = describeAll(x.Errors, indent+"  ")
+	case interface{ Unwrap() error }:
+		children = append(children, describe(x.Unwrap(), indent+"  "))
+	}
+
+	s := fmt.Sprintf("%s%T code=%s keyword=%q instance=%q location=%q schema=%s value=%s expected=%s",
+		indent, err, e.Code(), e.Keyword(), e.InstanceLocation(), e.KeywordLocation(), schemaId(e.Schema()), value(e.Value()), value(e.Expected()))
+
+	// the messages of the subschema errors depend on the order of their
+	// errors and a value may be an object which is being updated
+	switch e.Value().(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		if _, ok := err.(*jsonschema.ErrNotNot); !ok && children == nil {
+			s += fmt.Sprintf(" message=%q", err.Error())
+		}
+	}
+
+	return strings.Join(append([]string{s}, children...), "\n")
+}
+
+func describeAll(errs []error, indent string) []string {
+	var children []string
+	for _, err := range errs {
+		children = append(children, describe(err, indent))
+	}
+	return children
+}
+
+func schemaId(s *jsonschema.Schema) string {
+	if s == nil || s.Id == nil {
+		return "<nil>"
+	}
+	return s.Id.String()
+}
+
+func value(x interface{}) string {
+	switch y := x.(type) {
+	case *jsonschema.Schema:
+		return schemaId(y)
+	case []*jsonschema.Schema:
+		ids := make([]string, len(y))
+		for i, s := range y {
+			ids[i] = schemaId(s)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	data, err := json.Marshal(x)
+	if err != nil {
+		return fmt.Sprintf("%#v", x)
+	}
+
+	// compare the numbers by value
+	var v interface{}
+	json.Unmarshal(data, &v)
+	data, _ = json.Marshal(v)
+	return string(data)
+}
+`
+
+func TestBooleanSchemas(t *testing.T) {
+	// boolean root schemas (in the default dialect)
+	for def, valid := range map[string]bool{`true`: true, `false`: false} {
+		schema, err := RootEnv.BuildSchema("", []byte(def))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = schema.ValidateData([]byte(`{"foo": 1}`))
+		if (err == nil) != valid {
+			t.Errorf("%s: expected valid=%v but was %v", def, valid, err)
+		}
+	}
+
+	// boolean $ref targets next to a $ref which overrides its siblings
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$ref": "#/definitions/f",
+		"definitions": {"f": false}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = schema.ValidateData([]byte(`1`))
+	if err == nil {
+		t.Fatalf("expected an error but non were generated")
+	}
+}
+
+func TestDialectFromSchemaKeyword(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"$schema": "http://json-schema.org/draft-06/schema#",
+		"const": 1
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = schema.ValidateData([]byte(`2`))
+	if err == nil {
+		t.Fatalf("expected an error but non were generated")
+	}
+}
+
+func TestDraft3FromSchemaKeyword(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"$schema": "http://json-schema.org/draft-03/schema#",
+		"properties": {
+			"foo": {"type": "integer", "required": true}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = schema.ValidateData([]byte(`{}`))
+	if err == nil {
+		t.Fatalf("expected an error but non were generated")
+	}
+}
+
+func TestRefWithDynamicRef(t *testing.T) {
+	schema, err := RootEnv.BuildSchema("", []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/a",
+		"$dynamicRef": "#/$defs/b",
+		"$defs": {
+			"a": {"type": "string"},
+			"b": {"maxLength": 2}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for data, valid := range map[string]bool{`"ab"`: true, `"abc"`: false, `1`: false} {
+		err = schema.ValidateData([]byte(data))
+		if (err == nil) != valid {
+			t.Errorf("%s: expected valid=%v but was %v", data, valid, err)
+		}
+	}
+}
+
+func TestRegisterDialect(t *testing.T) {
+	env := RootEnv.Clone()
+
+	draft7, _ := env.DialectVocabulary("http://json-schema.org/draft-07/schema#")
+	formats, _ := env.DialectFormats("http://json-schema.org/draft-07/schema#")
+
+	extensions := NewVocabulary("http://example.com/vocab/even")
+	extensions.RegisterKeyword(&evenValidator{}, 1000, "x-even")
+
+	err := env.RegisterDialect("http://example.com/schema#", []*Vocabulary{draft7, extensions}, formats, []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "http://example.com/schema#",
+		"allOf": [{ "$ref": "http://json-schema.org/draft-07/schema#" }],
+		"properties": {
+			"x-even": { "type": "boolean" }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the draft-04 schema uses a boolean exclusiveMaximum
+	_, err = env.RegisterSchema("", []byte(`{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"id": "http://example.com/draft4.json#",
+		"maximum": 10,
+		"exclusiveMaximum": true
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.BuildSchema("", []byte(`{
+		"$schema": "http://example.com/schema#",
+		"x-even": 1
+	}`))
+	if err == nil {
+		t.Fatalf("expected an error but non were generated")
+	}
+
+	schema, err := env.BuildSchema("", []byte(`{
+		"$schema": "http://example.com/schema#",
+		"x-even": true,
+		"allOf": [{ "$ref": "http://example.com/draft4.json#" }]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for data, valid := range map[string]bool{`4`: true, `3`: false, `10`: false} {
+		err = schema.ValidateData([]byte(data))
+		if valid && err != nil {
+			t.Errorf("%s: error: %s", data, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("%s: expected an error but non were generated", data)
+		}
+	}
+}
+
+type evenValidator struct {
+	even bool
+}
+
+func (v *evenValidator) Setup(builder Builder) error {
+	x, _ := builder.GetKeyword("x-even")
+	v.even, _ = x.(bool)
+	return nil
+}
+
+func (v *evenValidator) Validate(x interface{}, ctx *Context) {
+	i, ok, err := toInteger(x)
+	if v.even && ok && err == nil && i%2 != 0 {
+		ctx.Report(fmt.Errorf("%d is not even", i))
+	}
+}
+
+func TestMetaSchemaVocabularies(t *testing.T) {
+	env := RootEnv.Clone()
+
+	_, err := env.RegisterSchema("", []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "http://localhost:1234/metaschema-no-validation.json",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/applicator": true,
+			"https://json-schema.org/draft/2020-12/vocab/core": true
+		},
+		"$dynamicAnchor": "meta",
+		"allOf": [
+			{"$ref": "https://json-schema.org/draft/2020-12/meta/applicator"},
+			{"$ref": "https://json-schema.org/draft/2020-12/meta/core"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := env.BuildSchema("", []byte(`{
+		"$schema": "http://localhost:1234/metaschema-no-validation.json",
+		"properties": {
+			"badProperty": {"not": {}},
+			"numberProperty": {"minimum": 10}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = schema.ValidateData([]byte(`{"numberProperty": 1}`))
+	if err != nil {
+		t.Fatalf("error: %s", err)
+	}
+
+	err = schema.ValidateData([]byte(`{"badProperty": 1}`))
+	if err == nil {
+		t.Fatalf("expected an error but non were generated")
+	}
+}
+
+func load_test_data(path string) []byte {
+	data, err := ioutil.ReadFile("testdata/" + path)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func load_test_json(path string, v interface{}) {
+	dec := json.NewDecoder(bytes.NewReader(load_test_data(path)))
+	dec.UseNumber()
+	err := dec.Decode(v)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func run_test_suite(t *testing.T, path string, options ...ValidateOption) {
+	t.Logf("- %s", path)
+
+	var suite []struct {
+		Description string          `json:"description"`
+		SchemaDef   json.RawMessage `json:"schema"`
+		Tests       []struct {
+			Description string      `json:"description"`
+			Data        interface{} `json:"data"`
+			Valid       bool        `json:"valid"`
+		}
+	}
+
+	load_test_json(path, &suite)
+
+	var (
+		passed int
+		failed int
+	)
+
+	env := RootEnv.Clone()
+	env.Transport = &testTransport{}
+	err := env.SetDefaultDialect(testSuiteDialects[strings.SplitN(path, "/", 2)[0]])
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	for _, group := range suite {
 		t.Logf("  - %s:", group.Description)
@@ -111,7 +2824,7 @@ func run_test_suite(t *testing.T, path string) {
 		}
 
 		for _, test := range group.Tests {
-			err := schema.Validate(test.Data)
+			err := schema.Validate(test.Data, options...)
 			if test.Valid && err == nil {
 				passed++
 				t.Logf("    \x1B[32m✓\x1B[0m %s", test.Description)
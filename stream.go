@@ -0,0 +1,642 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ValidateReader validates the JSON document read from r without decoding
+// all of it: the keywords which only need the members seen so far (like
+// `type`, `properties`, `items`, `required` and `maxItems`) are checked
+// while the objects and arrays are read and only the values which need to
+// be validated as a whole (like the values of schemas with `enum`,
+// `uniqueItems` or `oneOf`, and the strings, numbers, booleans and nulls)
+// are decoded before they are validated.
+//
+// The errors are those of Schema.ValidateData, except that the Value of the
+// errors of the objects and arrays which were not decoded is an empty
+// object or array. Syntax errors of the document are returned as is.
+//
+// ValidateReader takes no ValidateOptions: the options update the instance
+// (like WithDefaults, WithTypeCoercion and WithRemoveAdditional) and the
+// keywords which are checked while the objects and arrays are read (like
+// `required`) would have to see those updates. Use ValidateData (or
+// DecodeValid) with the options instead.
+func (s *Schema) ValidateReader(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	v := &streamValidator{dec: dec, streamable: map[*Schema]bool{}}
+
+	var result error
+	err := v.value([]*streamApplication{{
+		via:     s,
+		deliver: func(err error) { result = err },
+	}})
+	if err != nil {
+		return err
+	}
+	return result
+}
+
+type streamValidator struct {
+	dec *json.Decoder
+
+	// streamable caches the result of isStreamable by schema.
+	streamable map[*Schema]bool
+}
+
+// streamApplication is a schema which is applied to the next value of the
+// stream by the validator at index of the evaluation parent (or to the
+// document when parent is nil). deliver receives the error of the value.
+type streamApplication struct {
+	parent   *streamEvaluation
+	index    int
+	via      *Schema
+	hasToken bool
+	property string
+	item     int
+	deliver  func(err error)
+}
+
+// streamEvaluation is the evaluation of a schema against an object or array
+// which is read from the stream. It is the equivalent of a frame of the
+// Context: the evaluations of the subschemas which apply to the same value
+// (like those of `allOf`) are its branches.
+type streamEvaluation struct {
+	parent   *streamEvaluation
+	keyword  string
+	via      *Schema
+	schema   *Schema
+	inPlace  bool
+	hasToken bool
+	property string
+	item     int
+
+	object bool
+	count  int
+	names  map[string]bool
+
+	// errors are the errors of the validators by index.
+	errors [][]error
+
+	branches []*streamBranch
+}
+
+// streamBranch is the evaluation of an in-place subschema by the validator
+// at index.
+type streamBranch struct {
+	index int
+	eval  *streamEvaluation
+}
+
+// value validates the next value of the stream against the schemas of apps.
+func (v *streamValidator) value(apps []*streamApplication) error {
+	t, err := v.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := t.(json.Delim)
+	if !ok {
+		validateDecoded(apps, t)
+		return nil
+	}
+
+	streamed := true
+	for _, app := range apps {
+		if !v.isStreamable(refTarget(app.via), map[*Schema]bool{}) {
+			streamed = false
+			break
+		}
+	}
+
+	if !streamed {
+		x, err := v.decode(delim)
+		if err != nil {
+			return err
+		}
+		validateDecoded(apps, x)
+		return nil
+	}
+
+	var (
+		roots = make([]*streamEvaluation, len(apps))
+		evals []*streamEvaluation
+	)
+	for i, app := range apps {
+		e := &streamEvaluation{
+			parent:   app.parent,
+			via:      app.via,
+			schema:   refTarget(app.via),
+			hasToken: app.hasToken,
+			property: app.property,
+			item:     app.item,
+			object:   delim == '{',
+		}
+		if app.parent != nil {
+			e.keyword = app.parent.validatorKeyword(app.index)
+		}
+		roots[i] = e
+		evals = append(evals, e.open()...)
+	}
+
+	if delim == '{' {
+		err = v.object(evals)
+	} else {
+		err = v.array(evals)
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, app := range apps {
+		app.deliver(roots[i].close())
+	}
+	return nil
+}
+
+func (v *streamValidator) object(evals []*streamEvaluation) error {
+	for v.dec.More() {
+		t, err := v.dec.Token()
+		if err != nil {
+			return err
+		}
+		k, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("invalid object key: %v", t)
+		}
+
+		var apps []*streamApplication
+		for _, e := range evals {
+			apps = append(apps, e.propertyApplications(k)...)
+		}
+
+		err = v.value(apps)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := v.dec.Token()
+	return err
+}
+
+func (v *streamValidator) array(evals []*streamEvaluation) error {
+	for i := 0; v.dec.More(); i++ {
+		var apps []*streamApplication
+		for _, e := range evals {
+			apps = append(apps, e.itemApplications(i)...)
+		}
+
+		err := v.value(apps)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := v.dec.Token()
+	return err
+}
+
+// decode reads the rest of the object or array which starts with delim.
+func (v *streamValidator) decode(delim json.Delim) (interface{}, error) {
+	next := func() (interface{}, error) {
+		t, err := v.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := t.(json.Delim); ok {
+			return v.decode(d)
+		}
+		return t, nil
+	}
+
+	if delim == '[' {
+		y := []interface{}{}
+		for v.dec.More() {
+			m, err := next()
+			if err != nil {
+				return nil, err
+			}
+			y = append(y, m)
+		}
+		_, err := v.dec.Token()
+		return y, err
+	}
+
+	y := map[string]interface{}{}
+	for v.dec.More() {
+		t, err := v.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		k, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid object key: %v", t)
+		}
+		m, err := next()
+		if err != nil {
+			return nil, err
+		}
+		y[k] = m
+	}
+	_, err := v.dec.Token()
+	return y, err
+}
+
+// isStreamable returns true when all the validators of schema (and of its
+// in-place subschemas) can validate an object or array while it is read.
+func (v *streamValidator) isStreamable(schema *Schema, seen map[*Schema]bool) bool {
+	if streamable, found := v.streamable[schema]; found {
+		return streamable
+	}
+	if seen[schema] || schema.tracksEvaluation {
+		// loops are reported by the Context
+		return false
+	}
+	seen[schema] = true
+
+	streamable := true
+	for _, validator := range schema.Validators {
+		switch x := validator.(type) {
+		case *definitionsValidator, *defsValidator, *annotationValidator,
+			*falseValidator, *typeValidator, *integralTypeValidator, *nullableTypeValidator,
+			*multipleOfValidator, *divisibleByValidator, *maximumValidator, *minimumValidator,
+			*exclusiveMaximumValidator, *exclusiveMinimumValidator,
+			*maxLengthValidator, *minLengthValidator, *patternValidator,
+			*maxItemsValidator, *minItemsValidator, *itemsValidator,
+			*maxPropertiesValidator, *minPropertiesValidator, *requiredValidator,
+			*propertiesValidator, *propertyNamesValidator:
+
+		case *uniqueItemsValidator:
+			streamable = !x.unique
+
+		case *dependenciesValidator:
+			for _, d := range x.dependencies {
+				if _, ok := d.([]string); !ok {
+					streamable = false
+				}
+			}
+
+		case *allOfValidator:
+			for _, s := range x.schemas {
+				if !v.isStreamable(refTarget(s), seen) {
+					streamable = false
+				}
+			}
+
+		case *refValidator:
+			streamable = v.isStreamable(refTarget(x.schema), seen)
+
+		default:
+			streamable = false
+		}
+
+		if !streamable {
+			break
+		}
+	}
+
+	v.streamable[schema] = streamable
+	return streamable
+}
+
+// validateDecoded validates the decoded value x against the schemas of
+// apps. Like in propertiesValidator the schemas of the same validator get
+// the value updated by the schemas before them (like a json.Number which
+// was converted to an int64).
+func validateDecoded(apps []*streamApplication, x interface{}) {
+	var (
+		prev *streamApplication
+		y    interface{}
+	)
+	for _, app := range apps {
+		if prev == nil || prev.parent != app.parent || prev.index != app.index {
+			y = x
+		}
+		if z, err := app.validate(y); err == nil {
+			y = z
+		}
+		prev = app
+	}
+}
+
+// validate validates the decoded value x and returns the updated value.
+func (app *streamApplication) validate(x interface{}) (interface{}, error) {
+	var (
+		y   interface{}
+		err error
+	)
+
+	if app.parent == nil {
+		y, err = newContext().ValidateValueWith(x, app.via)
+	} else {
+		ctx := app.parent.context(app.parent.validatorKeyword(app.index))
+		y, err = ctx.validateValueWith(x, app.via, app.hasToken, app.property, app.item, false)
+	}
+
+	app.deliver(err)
+	return y, err
+}
+
+// open runs the validators which check the type of the value and adds the
+// branches of the in-place subschemas. It returns e followed by all its
+// branches.
+func (e *streamEvaluation) open() []*streamEvaluation {
+	e.errors = make([][]error, len(e.schema.Validators))
+	if e.object {
+		e.names = map[string]bool{}
+	}
+
+	evals := []*streamEvaluation{e}
+
+	branch := func(i int, schema *Schema) {
+		b := &streamEvaluation{
+			parent:  e,
+			keyword: e.validatorKeyword(i),
+			via:     schema,
+			schema:  refTarget(schema),
+			inPlace: true,
+			object:  e.object,
+		}
+		e.branches = append(e.branches, &streamBranch{i, b})
+		evals = append(evals, b.open()...)
+	}
+
+	for i, validator := range e.schema.Validators {
+		switch x := validator.(type) {
+		case *falseValidator:
+			e.report(i, &ErrFalseSchema{})
+
+		case *typeValidator:
+			e.checkType(i, x.expects)
+
+		case *integralTypeValidator:
+			e.checkType(i, x.expects)
+
+		case *nullableTypeValidator:
+			if len(x.expects) > 0 {
+				e.checkType(i, x.expects)
+			}
+
+		case *allOfValidator:
+			for _, s := range x.schemas {
+				branch(i, s)
+			}
+
+		case *refValidator:
+			branch(i, x.schema)
+		}
+	}
+
+	return evals
+}
+
+func (e *streamEvaluation) checkType(i int, expects []PrimitiveType) {
+	for _, t := range expects {
+		if (e.object && t == ObjectType) || (!e.object && t == ArrayType) {
+			return
+		}
+	}
+	e.report(i, &ErrInvalidType{Types: expects})
+}
+
+// propertyApplications returns the schemas which apply to the property k
+// and counts the property.
+func (e *streamEvaluation) propertyApplications(k string) []*streamApplication {
+	if !e.object {
+		return nil
+	}
+	e.names[k] = true
+	e.count++
+
+	var apps []*streamApplication
+	apply := func(i int, schema *Schema) {
+		apps = append(apps, &streamApplication{
+			parent:   e,
+			index:    i,
+			via:      schema,
+			hasToken: true,
+			property: k,
+			item:     -1,
+			deliver: func(err error) {
+				if err != nil {
+					e.report(i, &ErrInvalidProperty{Property: k, Err: err})
+				}
+			},
+		})
+	}
+
+	for i, validator := range e.schema.Validators {
+		switch x := validator.(type) {
+		case *propertiesValidator:
+			additional := true
+			if schema, found := x.properties[k]; found {
+				additional = false
+				apply(i, schema)
+			}
+			for _, pattern := range x.patterns {
+				if pattern.regexp.MatchString(k) {
+					additional = false
+					apply(i, pattern.schema)
+				}
+			}
+			if additional && x.additionalProperties != nil {
+				apply(i, x.additionalProperties)
+			}
+
+		case *propertyNamesValidator:
+			_, err := e.context(e.validatorKeyword(i)).ValidateValueWith(k, x.schema)
+			if err != nil {
+				e.report(i, &ErrInvalidPropertyName{Property: k, Err: err})
+			}
+		}
+	}
+
+	return apps
+}
+
+// itemApplications returns the schemas which apply to the item at index and
+// counts the item.
+func (e *streamEvaluation) itemApplications(index int) []*streamApplication {
+	if e.object {
+		return nil
+	}
+	e.count++
+
+	var apps []*streamApplication
+	apply := func(i int, schema *Schema) {
+		apps = append(apps, &streamApplication{
+			parent:   e,
+			index:    i,
+			via:      schema,
+			hasToken: true,
+			item:     index,
+			deliver: func(err error) {
+				if err != nil {
+					e.report(i, &ErrInvalidItem{Index: index, Err: err})
+				}
+			},
+		})
+	}
+
+	for i, validator := range e.schema.Validators {
+		if x, ok := validator.(*itemsValidator); ok {
+			switch {
+			case x.item != nil:
+				apply(i, x.item)
+			case index < len(x.items):
+				apply(i, x.items[index])
+			case x.items != nil && x.additionalItem != nil:
+				apply(i, x.additionalItem)
+			}
+		}
+	}
+
+	return apps
+}
+
+// close runs the validators which check the members of the value (after
+// closing the branches) and returns the error of the value.
+func (e *streamEvaluation) close() error {
+	branches := map[int][]error{}
+	for _, b := range e.branches {
+		branches[b.index] = append(branches[b.index], b.eval.close())
+	}
+
+	for i, validator := range e.schema.Validators {
+		switch x := validator.(type) {
+		case *maxItemsValidator:
+			if !e.object && e.count > x.max {
+				e.report(i, &ErrTooLong{Max: x.max})
+			}
+
+		case *minItemsValidator:
+			if !e.object && e.count < x.min {
+				e.report(i, &ErrTooShort{Min: x.min})
+			}
+
+		case *maxPropertiesValidator:
+			if e.object && e.count > x.max {
+				e.report(i, &ErrTooLong{Max: x.max})
+			}
+
+		case *minPropertiesValidator:
+			if e.object && e.count < x.min {
+				e.report(i, &ErrTooShort{Min: x.min})
+			}
+
+		case *requiredValidator:
+			for _, k := range x.required {
+				if e.object && !e.names[k] {
+					e.report(i, &ErrRequiredProperty{Property: k})
+				}
+			}
+
+		case *dependenciesValidator:
+			if !e.object {
+				break
+			}
+			for k, d := range x.dependencies {
+				if !e.names[k] {
+					continue
+				}
+				for _, dep := range d.([]string) {
+					if !e.names[dep] {
+						e.report(i, &ErrInvalidDependency{Property: k, Dependency: dep})
+					}
+				}
+			}
+
+		case *allOfValidator:
+			errs := branches[i]
+			if len(nonNilErrors(errs)) > 0 {
+				e.report(i, &ErrNotAllOf{Subschemas: x.schemas, Errors: errs})
+			}
+
+		case *refValidator:
+			if err := branches[i][0]; err != nil {
+				e.report(i, err)
+			}
+		}
+	}
+
+	var errs []error
+	for _, x := range e.errors {
+		errs = append(errs, x...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	ctx := e.context("")
+	l := len(ctx.stack) - 1
+	err := &ErrInvalidInstance{Errors: errs}
+	err.report("", ctx.stack[l].value, e.schema, ctx.frameLocation(l), "", "")
+	return err
+}
+
+// report reports err for the validator at index i.
+func (e *streamEvaluation) report(i int, err error) {
+	e.context(e.validatorKeyword(i)).Report(err)
+	e.errors[i] = append(e.errors[i], err)
+}
+
+func (e *streamEvaluation) validatorKeyword(i int) string {
+	if i < len(e.schema.keywords) {
+		return e.schema.keywords[i]
+	}
+	return ""
+}
+
+// context returns a Context with the frames of e and of its parents (like
+// the Context would have when validating the decoded value). keyword is the
+// keyword of the validator of e which is running.
+func (e *streamEvaluation) context(keyword string) *Context {
+	var chain []*streamEvaluation
+	for x := e; x != nil; x = x.parent {
+		chain = append(chain, x)
+	}
+
+	ctx := newContext()
+	ctx.stack = make([]contextStackFrame, len(chain), len(chain)+8)
+
+	valueId := 0
+	for i := range chain {
+		x := chain[len(chain)-1-i]
+		if i > 0 && !x.inPlace {
+			valueId++
+		}
+
+		// the members of the value are not kept
+		var value interface{} = []interface{}{}
+		if x.object {
+			value = map[string]interface{}{}
+		}
+
+		frame := &ctx.stack[i]
+		frame.valueId = valueId
+		frame.value = value
+		frame.schema = x.schema
+		frame.via = x.via
+		frame.hasToken = x.hasToken
+		frame.property = x.property
+		frame.index = x.item
+		frame.keyword = keyword
+		if i+1 < len(chain) {
+			frame.keyword = chain[len(chain)-2-i].keyword
+		}
+	}
+
+	return ctx
+}
+
+// refTarget returns the schema referenced by schema (or schema itself).
+func refTarget(schema *Schema) *Schema {
+	for schema.RefSchema != nil {
+		schema = schema.RefSchema
+	}
+	return schema
+}
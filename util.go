@@ -2,12 +2,54 @@ package jsonschema
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
+// deepCopy copies the maps and slices of a JSON value.
+func deepCopy(x interface{}) interface{} {
+	switch y := x.(type) {
+	case map[string]interface{}:
+		z := make(map[string]interface{}, len(y))
+		for k, v := range y {
+			z[k] = deepCopy(v)
+		}
+		return z
+	case []interface{}:
+		z := make([]interface{}, len(y))
+		for i, v := range y {
+			z[i] = deepCopy(v)
+		}
+		return z
+	default:
+		return x
+	}
+}
+
+// sameValue returns true when a and b are the same value (the same map or
+// slice for objects and arrays, and the same Go type for scalars).
+func sameValue(a, b interface{}) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+
+	switch a.(type) {
+	case map[string]interface{}, []interface{}:
+		va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	}
+
+	if ta != nil && !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
 func isEqual(a, b interface{}) (bool, error) {
 
 	// handle numbers mathematically
@@ -25,6 +67,42 @@ func isEqual(a, b interface{}) (bool, error) {
 		}
 	}
 
+	// compare containers element by element so nested numbers are
+	// compared mathematically as well
+	switch x := a.(type) {
+
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false, nil
+		}
+		for i := range x {
+			equal, err := isEqual(x[i], y[i])
+			if err != nil || !equal {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false, nil
+		}
+		for k, v := range x {
+			w, found := y[k]
+			if !found {
+				return false, nil
+			}
+			equal, err := isEqual(v, w)
+			if err != nil || !equal {
+				return false, err
+			}
+		}
+		return true, nil
+
+	}
+
 	// other values
 	return reflect.DeepEqual(a, b), nil
 
@@ -52,6 +130,38 @@ func toFloat(x interface{}) (float64, bool, error) {
 	}
 }
 
+func toInteger(x interface{}) (int64, bool, error) {
+	switch y := x.(type) {
+
+	case json.Number:
+		i, err := y.Int64()
+		if err == nil {
+			return i, true, nil
+		}
+		f, err := y.Float64()
+		if err != nil {
+			return 0, true, err
+		}
+		if f != math.Trunc(f) {
+			return 0, true, fmt.Errorf("%s is not an integer", y)
+		}
+		return int64(f), true, nil
+
+	case int64:
+		return y, true, nil
+
+	case float64:
+		if y != math.Trunc(y) {
+			return 0, true, fmt.Errorf("%v is not an integer", y)
+		}
+		return int64(y), true, nil
+
+	default:
+		return 0, false, nil
+
+	}
+}
+
 func isRef(x interface{}) (string, bool) {
 	m, ok := x.(map[string]interface{})
 	if !ok {
@@ -81,6 +191,48 @@ func escapeJSONPointer(s string) string {
 	return s
 }
 
+func unescapeJSONPointer(s string) string {
+	s = strings.Replace(s, "~1", "/", -1)
+	s = strings.Replace(s, "~0", "~", -1)
+	return s
+}
+
+// lookupJSONPointer returns the value at pointer in doc.
+func lookupJSONPointer(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid json pointer: %q", pointer)
+	}
+
+	x := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapeJSONPointer(token)
+
+		switch y := x.(type) {
+		case map[string]interface{}:
+			z, found := y[token]
+			if !found {
+				return nil, fmt.Errorf("unknown json pointer: %q", pointer)
+			}
+			x = z
+
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(y) {
+				return nil, fmt.Errorf("unknown json pointer: %q", pointer)
+			}
+			x = y[i]
+
+		default:
+			return nil, fmt.Errorf("unknown json pointer: %q", pointer)
+		}
+	}
+
+	return x, nil
+}
+
 func normalizeRef(r string) string {
 	if strings.IndexByte(r, '#') < 0 {
 		r += "#"
@@ -111,3 +263,15 @@ func refFragment(ref string) string {
 	idx := strings.IndexByte(ref, '#')
 	return ref[idx+1:]
 }
+
+// isSchema returns true when x is a schema object or a boolean schema.
+func isSchema(x interface{}) bool {
+	switch y := x.(type) {
+	case map[string]interface{}:
+		return y != nil
+	case bool:
+		return true
+	default:
+		return false
+	}
+}
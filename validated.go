@@ -0,0 +1,111 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// SchemaIdentifier is implemented by the types which are described by a
+// registered schema (see Validated).
+type SchemaIdentifier interface {
+	// SchemaId returns the id of the schema (like
+	// `https://example.com/order.json#/definitions/line`).
+	SchemaId() string
+}
+
+// EnvProvider is implemented by the types whose schema is registered in (or
+// reflected with) an Env other than RootEnv (see Validated).
+type EnvProvider interface {
+	// SchemaEnv returns the Env of the schema.
+	SchemaEnv() *Env
+}
+
+// Validated is a value of type T which is validated before it is decoded:
+// the JSON document is validated against the schema of T and the validated
+// instance is decoded into Value (see Schema.DecodeValid). The schema is the
+// schema registered with the id of T when T (or *T) implements
+// SchemaIdentifier and otherwise the schema reflected from T (see
+// Reflector). The schemas are registered in (or built with) RootEnv unless
+// T (or *T) implements EnvProvider. Like Env.Schema, Validated doesn't
+// synchronize with the registration of the schemas: they must be registered
+// before the documents are decoded.
+//
+// Validated fields make encoding/json validate the parts of a document
+// (like the body of a request) while decoding it; the errors are the
+// validation errors (like *ErrInvalidInstance) or a *DecodeError.
+type Validated[T any] struct {
+	Value T
+}
+
+// UnmarshalJSON validates data and decodes it into v.Value.
+func (v *Validated[T]) UnmarshalJSON(data []byte) error {
+	schema, err := validatedSchema[T]()
+	if err != nil {
+		return err
+	}
+
+	var value T
+	err = schema.DecodeValid(data, &value)
+	if err != nil {
+		return err
+	}
+
+	v.Value = value
+	return nil
+}
+
+// MarshalJSON encodes v.Value.
+func (v Validated[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Value)
+}
+
+// reflectedSchemas caches the schemas reflected for Validated by type and
+// Env.
+var reflectedSchemas sync.Map
+
+type reflectedSchemaKey struct {
+	typ reflect.Type
+	env *Env
+}
+
+func validatedSchema[T any]() (*Schema, error) {
+	var zero T
+
+	env := RootEnv
+	if p, ok := interface{}(zero).(EnvProvider); ok {
+		env = p.SchemaEnv()
+	} else if p, ok := interface{}(&zero).(EnvProvider); ok {
+		env = p.SchemaEnv()
+	}
+
+	id, ok := interface{}(zero).(SchemaIdentifier)
+	if !ok {
+		id, ok = interface{}(&zero).(SchemaIdentifier)
+	}
+	if ok {
+		schema, found := env.Schema(id.SchemaId())
+		if !found {
+			return nil, fmt.Errorf("jsonschema: unknown schema %s", id.SchemaId())
+		}
+		return schema, nil
+	}
+
+	key := reflectedSchemaKey{typ: reflect.TypeOf((*T)(nil)).Elem(), env: env}
+	if schema, found := reflectedSchemas.Load(key); found {
+		return schema.(*Schema), nil
+	}
+
+	data, err := (&Reflector{}).ReflectType(key.typ)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := env.BuildSchema("", data)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, _ := reflectedSchemas.LoadOrStore(key, schema)
+	return actual.(*Schema), nil
+}
@@ -0,0 +1,306 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// adaptValue converts the Go value x to the values the validators
+// understand (the values decoded by encoding/json): objects become
+// map[string]interface{}, arrays []interface{} and numbers int64, float64
+// or json.Number. Structs are converted through their `json` tags like
+// encoding/json does. Values which are already converted are returned as
+// is (the objects and arrays are only copied when one of their members is
+// converted) and values which have no JSON representation are kept.
+func adaptValue(x interface{}) interface{} {
+	switch y := x.(type) {
+	case nil, bool, string, json.Number, int64, float64:
+		return x
+
+	case map[string]interface{}:
+		var z map[string]interface{}
+		for k, m := range y {
+			n := adaptValue(m)
+			if z == nil && !sameValue(m, n) {
+				z = make(map[string]interface{}, len(y))
+				for k, m := range y {
+					z[k] = m
+				}
+			}
+			if z != nil {
+				z[k] = n
+			}
+		}
+		if z == nil {
+			return y
+		}
+		return z
+
+	case []interface{}:
+		var z []interface{}
+		for i, m := range y {
+			n := adaptValue(m)
+			if z == nil && !sameValue(m, n) {
+				z = make([]interface{}, len(y))
+				copy(z, y)
+			}
+			if z != nil {
+				z[i] = n
+			}
+		}
+		if z == nil {
+			return y
+		}
+		return z
+	}
+
+	v, ok := adaptReflectValue(reflect.ValueOf(x))
+	if !ok {
+		return x
+	}
+	return v
+}
+
+// adaptReflectValue converts v like adaptValue. It returns false when v has
+// no JSON representation.
+func adaptReflectValue(v reflect.Value) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, true
+	}
+
+	if v.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(v.Type()).Implements(jsonMarshalerType) {
+		v = v.Addr()
+	}
+	if v.Type().Implements(jsonMarshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, true
+		}
+		return adaptMarshaler(v.Interface().(json.Marshaler))
+	}
+	if v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, true
+		}
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, false
+		}
+		return string(text), true
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil, true
+		}
+		return adaptReflectValue(v.Elem())
+
+	case reflect.Bool:
+		return v.Bool(), true
+
+	case reflect.String:
+		if v.Type() == reflect.TypeOf(json.Number("")) {
+			return json.Number(v.String()), true
+		}
+		return v.String(), true
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			return json.Number(strconv.FormatUint(u, 10)), true
+		}
+		return int64(u), true
+
+	case reflect.Float32:
+		// use the shortest decimal of the float32 (like encoding/json) so
+		// 0.1 stays 0.1 instead of 0.10000000149011612
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(v.Float(), 'g', -1, 32), 64)
+		return f, true
+
+	case reflect.Float64:
+		return v.Float(), true
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, true
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), true
+		}
+		return adaptArray(v)
+
+	case reflect.Array:
+		return adaptArray(v)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, true
+		}
+		z := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, ok := adaptKey(iter.Key())
+			if !ok {
+				return nil, false
+			}
+			m, ok := adaptReflectValue(iter.Value())
+			if !ok {
+				return nil, false
+			}
+			z[k] = m
+		}
+		return z, true
+
+	case reflect.Struct:
+		return adaptStruct(v)
+
+	default:
+		return nil, false
+	}
+}
+
+func adaptArray(v reflect.Value) (interface{}, bool) {
+	z := make([]interface{}, v.Len())
+	for i := range z {
+		m, ok := adaptReflectValue(v.Index(i))
+		if !ok {
+			return nil, false
+		}
+		z[i] = m
+	}
+	return z, true
+}
+
+// adaptKey returns the property name of a map key. Like encoding/json it
+// accepts strings, integers and encoding.TextMarshalers, and for the keys
+// of interface maps (like those decoded from YAML) any scalar.
+func adaptKey(k reflect.Value) (string, bool) {
+	if k.Kind() == reflect.Interface {
+		if k.IsNil() {
+			return "", false
+		}
+		k = k.Elem()
+	}
+
+	if k.Kind() == reflect.String {
+		return k.String(), true
+	}
+	if k.Type().Implements(textMarshalerType) {
+		text, err := k.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err == nil
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), true
+	case reflect.Bool, reflect.Float32, reflect.Float64:
+		return fmt.Sprint(k.Interface()), true
+	default:
+		return "", false
+	}
+}
+
+// adaptStruct converts the exported fields of the struct v (see
+// structFields) to the properties of an object.
+func adaptStruct(v reflect.Value) (interface{}, bool) {
+	fields := structFields(v.Type())
+	z := make(map[string]interface{}, len(fields))
+
+	for _, field := range fields {
+		fv, ok := readFieldByIndex(v, field.index)
+		if !ok {
+			// a field of a nil embedded struct pointer
+			continue
+		}
+		if field.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		m, ok := adaptReflectValue(fv)
+		if !ok {
+			return nil, false
+		}
+
+		if field.quoted {
+			switch y := m.(type) {
+			case bool:
+				m = strconv.FormatBool(y)
+			case int64:
+				m = strconv.FormatInt(y, 10)
+			case float64:
+				m = strconv.FormatFloat(y, 'g', -1, 64)
+			case json.Number:
+				m = string(y)
+			case string:
+				m = strconv.Quote(y)
+			}
+		}
+
+		z[field.name] = m
+	}
+
+	return z, true
+}
+
+// readFieldByIndex returns the field of v at index. It returns false when
+// the field is in a nil embedded struct pointer.
+func readFieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue reports whether v is empty for `omitempty` (like
+// encoding/json).
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// adaptMarshaler converts the JSON produced by m.
+func adaptMarshaler(m json.Marshaler) (interface{}, bool) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, false
+	}
+
+	var x interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = dec.Decode(&x)
+	if err != nil {
+		return nil, false
+	}
+	return x, true
+}
@@ -0,0 +1,48 @@
+package jsonschema
+
+import (
+	"reflect"
+)
+
+// Vocabulary is a named group of keywords. Dialects which declare the
+// vocabulary in the `$vocabulary` keyword of their meta schema can use all
+// of its keywords.
+type Vocabulary struct {
+	uri        string
+	validators map[string]*validator
+}
+
+func NewVocabulary(uri string) *Vocabulary {
+	return &Vocabulary{
+		uri:        uri,
+		validators: map[string]*validator{},
+	}
+}
+
+func (v *Vocabulary) URI() string {
+	return v.uri
+}
+
+func (v *Vocabulary) RegisterKeyword(val Validator, priority int, key string, additionalKeys ...string) {
+	registerKeyword(v.validators, val, priority, key, additionalKeys...)
+}
+
+func registerKeyword(validators map[string]*validator, v Validator, priority int, key string, additionalKeys ...string) {
+	keys := append([]string{key}, additionalKeys...)
+
+	for _, key := range keys {
+		if _, found := validators[key]; found {
+			panic("keyword is already registered")
+		}
+	}
+
+	rt := reflect.TypeOf(v)
+	if rt.Kind() != reflect.Ptr {
+		panic("Validator must be a pointer")
+	}
+
+	validator := &validator{keys, priority, rt.Elem()}
+	for _, key := range keys {
+		validators[key] = validator
+	}
+}